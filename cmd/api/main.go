@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tsaqiffatih/minddrift-server/config"
@@ -11,6 +17,7 @@ import (
 	"github.com/tsaqiffatih/minddrift-server/internal/model"
 	"github.com/tsaqiffatih/minddrift-server/internal/repository"
 	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/internal/storage"
 )
 
 func main() {
@@ -20,14 +27,27 @@ func main() {
 
 	err := db.AutoMigrate(
 		&model.User{},
+		&model.RefreshToken{},
+		&model.RecoveryCode{},
+		&model.ActionToken{},
 		&model.Article{},
 		&model.Category{},
 		&model.Tag{},
 		&model.ArticleVersion{},
+		&model.ArticleStatusHistory{},
+		&model.SlugRedirect{},
 		&model.Comment{},
+		&model.SpamToken{},
+		&model.SpamCorpus{},
 		&model.Image{},
+		&model.ImageVariant{},
 		&model.SEOMetadata{},
 		&model.Analytic{},
+		&model.AnalyticsEvent{},
+		&model.AnalyticsVisitor{},
+		&model.AnalyticsSalt{},
+		&model.AnalyticsRollup{},
+		&model.AnalyticsSourceRollup{},
 		&model.Backup{},
 	)
 	if err != nil {
@@ -35,21 +55,110 @@ func main() {
 	}
 
 	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	actionTokenRepo := repository.NewActionTokenRepository(db)
+	articleRepo := repository.NewArticleRepository(db)
+	articleVersionRepo := repository.NewArticleVersionRepository(db)
+	articleWorkflowRepo := repository.NewArticleWorkflowRepository(db)
+	slugRedirectRepo := repository.NewSlugRedirectRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	spamRepo := repository.NewSpamRepository(db)
+	imageRepo := repository.NewImageRepository(db)
+	seoRepo := repository.NewSEORepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	articleTagRepo := repository.NewArticleTagRepository(db)
+	searchRepo := repository.NewSearchRepository(db, cfg.SearchLanguage)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	analyticsReportRepo := repository.NewAnalyticsReportRepository(db)
 
-	userService := service.NewUserService(userRepo, cfg)
+	if err := searchRepo.EnsureSearchVector(); err != nil {
+		log.Fatalf("❌ Failed to prepare article search: %v", err)
+	}
+
+	fileStorage, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize storage: %v", err)
+	}
+
+	authService := service.NewAuthService(refreshTokenRepo, cfg)
+	userService := service.NewUserService(userRepo, recoveryCodeRepo, actionTokenRepo, authService, cfg)
+	articleService := service.NewArticleService(articleRepo, articleVersionRepo, slugRedirectRepo)
+	articleWorkflowService := service.NewArticleWorkflowService(articleRepo, articleWorkflowRepo)
+	spamService := service.NewSpamService(spamRepo, service.DefaultSpamChecks(commentRepo, cfg)...)
+	commentService := service.NewCommentService(commentRepo, articleRepo, spamService, cfg)
+	imageService := service.NewImageService(imageRepo, articleRepo, fileStorage, cfg)
+	seoService := service.NewSEOService(seoRepo, articleRepo, imageRepo, cfg)
+	categoryService := service.NewCategoryService(categoryRepo, articleRepo)
+	tagService := service.NewTagService(tagRepo, articleTagRepo, articleRepo)
+	if err := tagService.BackfillTagKeys(); err != nil {
+		log.Fatalf("❌ Failed to prepare tag keys: %v", err)
+	}
+	feedService := service.NewFeedService(articleRepo, categoryRepo, tagRepo, seoRepo, cfg)
+	searchService := service.NewSearchService(searchRepo, cfg)
+	trafficClassifier, err := service.NewTrafficClassifier(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to load traffic source rules: %v", err)
+	}
+	analyticsCollector := service.NewAnalyticsCollector(analyticsRepo, cfg.AnalyticsFlushInterval, cfg.AnalyticsBatchSize)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, analyticsReportRepo, articleRepo, analyticsCollector, trafficClassifier, cfg)
 
 	userHandler := handler.NewUserHandler(userService, cfg)
+	authHandler := handler.NewAuthHandler(authService)
+	articleHandler := handler.NewArticleHandler(articleService)
+	articleWorkflowHandler := handler.NewArticleWorkflowHandler(articleWorkflowService)
+	commentHandler := handler.NewCommentHandler(commentService)
+	imageHandler := handler.NewImageHandler(imageService, cfg)
+	seoHandler := handler.NewSEOHandler(seoService)
+	feedHandler := handler.NewFeedHandler(feedService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	tagHandler := handler.NewTagHandler(tagService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
 
 	fmt.Println("✅ Database migration completed!")
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	articleScheduler := service.NewArticleScheduler(articleWorkflowRepo, cfg.SchedulerInterval)
+	articleScheduler.Start(ctx)
+
+	// the collector outlives ctx so hits from requests still draining on
+	// shutdown make it into its last flush
+	collectorCtx, stopCollector := context.WithCancel(context.Background())
+	analyticsCollector.Start(collectorCtx)
+
+	analyticsRollup := service.NewAnalyticsRollup(analyticsRepo, cfg.AnalyticsRollupInterval, cfg.AnalyticsEventRetention, cfg.AnalyticsHourlyRetention)
+	analyticsRollup.Start(ctx)
+
 	r := gin.Default()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("❌ Invalid trusted proxies: %v", err)
+	}
 
 	handlers := map[string]interface{}{
-		"user": userHandler,
+		"user":            userHandler,
+		"auth":            authHandler,
+		"article":         articleHandler,
+		"articleWorkflow": articleWorkflowHandler,
+		"comment":         commentHandler,
+		"image":           imageHandler,
+		"seo":             seoHandler,
+		"feed":            feedHandler,
+		"category":        categoryHandler,
+		"tag":             tagHandler,
+		"search":          searchHandler,
+		"analytics":       analyticsHandler,
 	}
 
 	RegisterRoutes(r, handlers, cfg)
 
+	if cfg.StorageDriver == "local" {
+		r.Static("/uploads", cfg.UploadDir)
+	}
+
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"message": "pong",
@@ -62,6 +171,28 @@ func main() {
 		port = "8080"
 	}
 
-	fmt.Printf("🚀 Server running at http://localhost:%s\n", port)
-	r.Run(":" + port)
+	server := &http.Server{
+		Addr:    ":" + port,
+		Handler: r,
+	}
+
+	go func() {
+		fmt.Printf("🚀 Server running at http://localhost:%s\n", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("❌ Server failed: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	fmt.Println("🛑 Shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Error shutting down server:", err)
+	}
+
+	stopCollector()
+	analyticsCollector.Wait()
 }
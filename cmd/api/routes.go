@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
 	"github.com/tsaqiffatih/minddrift-server/internal/handler"
 	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
 )
@@ -11,44 +12,151 @@ func RegisterRoutes(r *gin.Engine, handlers map[string]interface{}, cfg *config.
 	api := r.Group("/api")
 
 	authMiddleware := middleware.AuthMiddleware(cfg)
+	optionalAuthMiddleware := middleware.OptionalAuthMiddleware(cfg)
+	can := middleware.RequirePermission
 
 	userHandler := handlers["user"].(handler.UserHandler)
-	// articleHandler := handlers["article"].(*handler.ArticleHandler)
-	// imageHandler := handlers["image"].(*handler.ImageHandler)
+	authHandler := handlers["auth"].(handler.AuthHandler)
+	articleHandler := handlers["article"].(handler.ArticleHandler)
+	articleWorkflowHandler := handlers["articleWorkflow"].(handler.ArticleWorkflowHandler)
+	commentHandler := handlers["comment"].(handler.CommentHandler)
+	imageHandler := handlers["image"].(handler.ImageHandler)
+	seoHandler := handlers["seo"].(handler.SEOHandler)
+	feedHandler := handlers["feed"].(handler.FeedHandler)
+	categoryHandler := handlers["category"].(handler.CategoryHandler)
+	tagHandler := handlers["tag"].(handler.TagHandler)
+	searchHandler := handlers["search"].(handler.SearchHandler)
+	analyticsHandler := handlers["analytics"].(handler.AnalyticsHandler)
 
 	// User Routes
 	userRoutes := api.Group("/users")
 	{
 		userRoutes.POST("/register", userHandler.RegisterUser)
 		userRoutes.POST("/login", userHandler.LoginUser)
+		userRoutes.POST("/login/2fa", userHandler.LoginTwoFA)
 		userRoutes.POST("/auth/forgot-password", userHandler.RequestResetPassword)
 		userRoutes.POST("/auth/validate-reset-token", userHandler.ValidateResetToken)
 		userRoutes.POST("/auth/reset-password", userHandler.ResetPassword)
+		userRoutes.POST("/auth/refresh", authHandler.RefreshToken)
+		userRoutes.POST("/auth/logout", authHandler.Logout)
+		userRoutes.POST("/auth/logout-all", authMiddleware, authHandler.LogoutAll)
+
+		userRoutes.POST("/2fa/setup", authMiddleware, userHandler.SetupTwoFA)
+		userRoutes.POST("/2fa/enable", authMiddleware, userHandler.EnableTwoFA)
+		userRoutes.POST("/2fa/disable", authMiddleware, userHandler.DisableTwoFA)
 
 		userRoutes.GET("/verify-email", userHandler.VerifyEmail)
 		userRoutes.GET("/resend-email", userHandler.ResendEmail)
 
-		userRoutes.DELETE("/:id", authMiddleware, userHandler.DeleteUser)
+		userRoutes.DELETE("/:id", authMiddleware, can(constant.PermUserManage), userHandler.DeleteUser)
+		userRoutes.PUT("/:id/role", authMiddleware, can(constant.PermUserManage), userHandler.ChangeUserRole)
 		// userRoutes.GET("/:id", userHandler.GetUserByID)
 		// userRoutes.PUT("/:id", userHandler.UpdateUser)
 		// userRoutes.GET("/me", authMiddleware, userHandler.GetCurrentUser)
 	}
 
-	// // Article Routes
-	// articleRoutes := api.Group("/articles")
-	// {
-	// 	articleRoutes.POST("/", articleHandler.CreateArticle)
-	// 	articleRoutes.GET("/", articleHandler.GetAllArticles)
-	// 	articleRoutes.GET("/:id", articleHandler.GetArticleByID)
-	// 	articleRoutes.PUT("/:id", articleHandler.UpdateArticle)
-	// 	articleRoutes.DELETE("/:id", articleHandler.DeleteArticle)
-	// }
-
-	// // Image Routes
-	// imageRoutes := api.Group("/images")
-	// {
-	// 	imageRoutes.POST("/", imageHandler.UploadImage)
-	// 	imageRoutes.GET("/:id", imageHandler.GetImageByID)
-	// 	imageRoutes.DELETE("/:id", imageHandler.DeleteImage)
-	// }
+	// Article Routes
+	articleRoutes := api.Group("/articles")
+	{
+		articleRoutes.POST("", authMiddleware, can(constant.PermArticleCreate), articleHandler.CreateArticle)
+		articleRoutes.GET("", optionalAuthMiddleware, articleHandler.GetAllArticles)
+		articleRoutes.GET("/me", authMiddleware, articleHandler.GetMyArticles)
+		articleRoutes.GET("/search", searchHandler.SearchArticles)
+		articleRoutes.GET("/review-queue", authMiddleware, can(constant.PermArticleReview), articleWorkflowHandler.GetReviewQueue)
+		articleRoutes.GET("/slug/:slug", optionalAuthMiddleware, articleHandler.GetArticleBySlug)
+		articleRoutes.GET("/:id", optionalAuthMiddleware, articleHandler.GetArticleByID)
+		articleRoutes.PUT("/:id", authMiddleware, articleHandler.UpdateArticle)
+		articleRoutes.DELETE("/:id", authMiddleware, articleHandler.DeleteArticle)
+
+		articleRoutes.GET("/:id/versions", authMiddleware, articleHandler.GetArticleVersions)
+		articleRoutes.GET("/:id/versions/diff", authMiddleware, articleHandler.DiffArticleVersions)
+		articleRoutes.POST("/:id/versions/:versionId/restore", authMiddleware, articleHandler.RestoreArticleVersion)
+
+		articleRoutes.POST("/:id/status", authMiddleware, articleWorkflowHandler.ChangeArticleStatus)
+		articleRoutes.GET("/:id/status-history", authMiddleware, articleWorkflowHandler.GetStatusHistory)
+
+		articleRoutes.GET("/:id/comments", commentHandler.GetArticleComments)
+		articleRoutes.POST("/:id/comments", optionalAuthMiddleware, commentHandler.CreateComment)
+
+		articleRoutes.GET("/:id/images", optionalAuthMiddleware, imageHandler.GetArticleImages)
+
+		articleRoutes.GET("/:id/categories", optionalAuthMiddleware, categoryHandler.GetArticleCategories)
+		articleRoutes.PUT("/:id/categories", authMiddleware, categoryHandler.SetArticleCategories)
+		articleRoutes.GET("/:id/tags", optionalAuthMiddleware, tagHandler.GetArticleTags)
+		articleRoutes.PUT("/:id/tags", authMiddleware, tagHandler.SetArticleTags)
+		articleRoutes.GET("/:id/tags/suggestions", authMiddleware, tagHandler.SuggestArticleTags)
+
+		articleRoutes.GET("/:id/seo", optionalAuthMiddleware, seoHandler.GetSEOMetadata)
+		articleRoutes.PUT("/:id/seo", authMiddleware, seoHandler.UpdateSEOMetadata)
+		articleRoutes.DELETE("/:id/seo", authMiddleware, seoHandler.DeleteSEOMetadata)
+		articleRoutes.GET("/:id/seo/preview", optionalAuthMiddleware, seoHandler.PreviewSearchResult)
+		articleRoutes.GET("/:id/seo/score", authMiddleware, seoHandler.ScoreArticle)
+		articleRoutes.GET("/:id/seo/social", optionalAuthMiddleware, seoHandler.GetSocialMetadata)
+		articleRoutes.GET("/:id/seo/head", optionalAuthMiddleware, seoHandler.GetHeadFragment)
+	}
+
+	// Comment Routes
+	commentRoutes := api.Group("/comments")
+	{
+		commentRoutes.GET("/confirm", commentHandler.ConfirmGuestComment)
+		commentRoutes.GET("/moderation", authMiddleware, can(constant.PermCommentModerate), commentHandler.GetModerationQueue)
+		commentRoutes.PATCH("/:id/status", authMiddleware, commentHandler.ModerateComment)
+		commentRoutes.DELETE("/:id", authMiddleware, commentHandler.DeleteComment)
+	}
+
+	// Image Routes
+	imageRoutes := api.Group("/images")
+	{
+		imageRoutes.POST("", authMiddleware, can(constant.PermArticleCreate), imageHandler.UploadImage)
+		imageRoutes.POST("/remote", authMiddleware, can(constant.PermArticleCreate), imageHandler.UploadImageFromURL)
+		imageRoutes.GET("/me", authMiddleware, imageHandler.GetMyImages)
+		imageRoutes.GET("/:id", optionalAuthMiddleware, imageHandler.GetImageByID)
+		imageRoutes.PATCH("/:id", authMiddleware, imageHandler.UpdateImage)
+		imageRoutes.DELETE("/:id", authMiddleware, imageHandler.DeleteImage)
+	}
+
+	// Category Routes
+	categoryRoutes := api.Group("/categories")
+	{
+		categoryRoutes.GET("", categoryHandler.GetCategories)
+		categoryRoutes.GET("/slug/:slug", categoryHandler.GetCategoryBySlug)
+		categoryRoutes.GET("/:id", categoryHandler.GetCategoryByID)
+		categoryRoutes.POST("", authMiddleware, can(constant.PermCategoryManage), categoryHandler.CreateCategory)
+		categoryRoutes.PUT("/:id", authMiddleware, can(constant.PermCategoryManage), categoryHandler.UpdateCategory)
+		categoryRoutes.DELETE("/:id", authMiddleware, can(constant.PermCategoryManage), categoryHandler.DeleteCategory)
+	}
+
+	// Tag Routes
+	tagRoutes := api.Group("/tags")
+	{
+		tagRoutes.GET("", tagHandler.GetTags)
+		tagRoutes.GET("/autocomplete", tagHandler.AutocompleteTags)
+		tagRoutes.GET("/slug/:slug", tagHandler.GetTagBySlug)
+		tagRoutes.GET("/:id", tagHandler.GetTagByID)
+		tagRoutes.POST("", authMiddleware, can(constant.PermTagManage), tagHandler.CreateTag)
+		tagRoutes.PUT("/:id", authMiddleware, can(constant.PermTagManage), tagHandler.UpdateTag)
+		tagRoutes.DELETE("/:id", authMiddleware, can(constant.PermTagManage), tagHandler.DeleteTag)
+		tagRoutes.POST("/:id/merge", authMiddleware, can(constant.PermTagManage), tagHandler.MergeTags)
+	}
+
+	// Analytics Routes
+	analyticsRoutes := api.Group("/analytics")
+	{
+		analyticsRoutes.POST("/beacon", analyticsHandler.RecordBeacon)
+		analyticsRoutes.GET("/dashboard/overview", authMiddleware, analyticsHandler.GetOverview)
+		analyticsRoutes.GET("/dashboard/trends", authMiddleware, analyticsHandler.GetTrend)
+		analyticsRoutes.GET("/dashboard/top-articles", authMiddleware, analyticsHandler.GetTopArticles)
+		analyticsRoutes.GET("/dashboard/sources", authMiddleware, analyticsHandler.GetTrafficSources)
+	}
+
+	// Discovery Routes
+	r.GET("/robots.txt", feedHandler.GetRobots)
+	r.GET("/sitemap.xml", feedHandler.GetSitemap)
+	r.GET("/sitemaps/:page", feedHandler.GetSitemapPage)
+	feedRoutes := r.Group("/feeds")
+	{
+		feedRoutes.GET("/:format", feedHandler.GetSiteFeed)
+		feedRoutes.GET("/categories/:slug/:format", feedHandler.GetCategoryFeed)
+		feedRoutes.GET("/tags/:slug/:format", feedHandler.GetTagFeed)
+	}
 }
@@ -5,6 +5,8 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
@@ -23,6 +25,56 @@ type Config struct {
 	MindDriftEmail string
 	BaseURL        string
 	FrontendURL    string
+
+	// TrustedProxies lists the proxies whose X-Forwarded-For is believed
+	// when resolving client IPs. Empty trusts none.
+	TrustedProxies []string
+
+	SiteName        string
+	SiteDescription string
+	SiteLogoURL     string
+	TwitterSite     string
+	FeedItemLimit   int
+
+	SearchLanguage string
+
+	AnalyticsFlushInterval    time.Duration
+	AnalyticsBatchSize        int
+	AnalyticsRollupInterval   time.Duration
+	AnalyticsEventRetention   time.Duration
+	AnalyticsHourlyRetention  time.Duration
+	AnalyticsSourceRules      string
+	AnalyticsBeaconRateLimit  int
+	AnalyticsBeaconRateWindow time.Duration
+
+	SchedulerInterval time.Duration
+	AccessTokenTTL    time.Duration
+	RefreshTokenTTL   time.Duration
+
+	CommentEmailConfirmation bool
+	CommentBlocklist         []string
+	CommentRateLimit         int
+	CommentRateWindow        time.Duration
+
+	StorageDriver  string
+	UploadDir      string
+	UploadBaseURL  string
+	MaxUploadSize  int64
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3PublicURL    string
+	S3UsePathStyle bool
+
+	ImageOutputFormat  string
+	ImageJPEGQuality   int
+	ImageMaxWidth      int
+	ImageVariantWidths []int
+
+	RemoteFetchTimeout      time.Duration
+	RemoteFetchMaxRedirects int
 }
 
 func LoadConfig() *Config {
@@ -31,6 +83,14 @@ func LoadConfig() *Config {
 	}
 
 	port, _ := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	commentRateLimit, _ := strconv.Atoi(getEnv("COMMENT_RATE_LIMIT", "5"))
+	maxUploadSize, _ := strconv.ParseInt(getEnv("MAX_UPLOAD_SIZE", "5242880"), 10, 64)
+	jpegQuality, _ := strconv.Atoi(getEnv("IMAGE_JPEG_QUALITY", "82"))
+	imageMaxWidth, _ := strconv.Atoi(getEnv("IMAGE_MAX_WIDTH", "2560"))
+	maxRedirects, _ := strconv.Atoi(getEnv("REMOTE_FETCH_MAX_REDIRECTS", "3"))
+	feedItemLimit, _ := strconv.Atoi(getEnv("FEED_ITEM_LIMIT", "20"))
+	analyticsBatchSize, _ := strconv.Atoi(getEnv("ANALYTICS_BATCH_SIZE", "500"))
+	beaconRateLimit, _ := strconv.Atoi(getEnv("ANALYTICS_BEACON_RATE_LIMIT", "120"))
 
 	config := &Config{
 		DatabaseURL:    getEnv("DATABASE_URL", ""),
@@ -44,6 +104,93 @@ func LoadConfig() *Config {
 		MindDriftEmail: getEnv("MINDDRIFT_EMAIL", ""),
 		BaseURL:        getEnv("BASE_URL", ""),
 		FrontendURL:    getEnv("FRONTEND_URL", ""),
+
+		TrustedProxies: getListEnv("TRUSTED_PROXIES"),
+
+		SiteName:        getEnv("SITE_NAME", "MindDrift"),
+		SiteDescription: getEnv("SITE_DESCRIPTION", ""),
+		SiteLogoURL:     getEnv("SITE_LOGO_URL", ""),
+		TwitterSite:     getEnv("TWITTER_SITE", ""),
+		FeedItemLimit:   feedItemLimit,
+
+		SearchLanguage: strings.ToLower(getEnv("SEARCH_LANGUAGE", "indonesian")),
+
+		AnalyticsFlushInterval:    getDurationEnv("ANALYTICS_FLUSH_INTERVAL", 10*time.Second),
+		AnalyticsBatchSize:        analyticsBatchSize,
+		AnalyticsRollupInterval:   getDurationEnv("ANALYTICS_ROLLUP_INTERVAL", 5*time.Minute),
+		AnalyticsEventRetention:   getDurationEnv("ANALYTICS_EVENT_RETENTION", 30*24*time.Hour),
+		AnalyticsHourlyRetention:  getDurationEnv("ANALYTICS_HOURLY_RETENTION", 90*24*time.Hour),
+		AnalyticsSourceRules:      getEnv("ANALYTICS_SOURCE_RULES", ""),
+		AnalyticsBeaconRateLimit:  beaconRateLimit,
+		AnalyticsBeaconRateWindow: getDurationEnv("ANALYTICS_BEACON_RATE_WINDOW", time.Minute),
+
+		SchedulerInterval: getDurationEnv("SCHEDULER_INTERVAL", time.Minute),
+		AccessTokenTTL:    getDurationEnv("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:   getDurationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		CommentEmailConfirmation: getEnv("COMMENT_EMAIL_CONFIRMATION", "false") == "true",
+		CommentBlocklist:         getListEnv("COMMENT_BLOCKLIST"),
+		CommentRateLimit:         commentRateLimit,
+		CommentRateWindow:        getDurationEnv("COMMENT_RATE_WINDOW", 10*time.Minute),
+
+		StorageDriver:  getEnv("STORAGE_DRIVER", "local"),
+		UploadDir:      getEnv("UPLOAD_DIR", "./uploads"),
+		MaxUploadSize:  maxUploadSize,
+		S3Endpoint:     getEnv("S3_ENDPOINT", ""),
+		S3Region:       getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:       getEnv("S3_BUCKET", ""),
+		S3AccessKey:    getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:    getEnv("S3_SECRET_KEY", ""),
+		S3PublicURL:    getEnv("S3_PUBLIC_URL", ""),
+		S3UsePathStyle: getEnv("S3_USE_PATH_STYLE", "false") == "true",
+
+		ImageOutputFormat: strings.ToLower(getEnv("IMAGE_OUTPUT_FORMAT", "original")),
+		ImageJPEGQuality:  jpegQuality,
+		ImageMaxWidth:     imageMaxWidth,
+
+		RemoteFetchTimeout:      getDurationEnv("REMOTE_FETCH_TIMEOUT", 10*time.Second),
+		RemoteFetchMaxRedirects: maxRedirects,
+	}
+
+	for _, value := range getListEnv("IMAGE_VARIANT_WIDTHS") {
+		if width, err := strconv.Atoi(value); err == nil && width > 0 {
+			config.ImageVariantWidths = append(config.ImageVariantWidths, width)
+		}
+	}
+	if len(config.ImageVariantWidths) == 0 {
+		config.ImageVariantWidths = []int{320, 768, 1280}
+	}
+	if config.ImageJPEGQuality < 1 || config.ImageJPEGQuality > 100 {
+		config.ImageJPEGQuality = 82
+	}
+
+	if config.FeedItemLimit <= 0 || config.FeedItemLimit > 100 {
+		config.FeedItemLimit = 20
+	}
+
+	// text search configurations shipped with Postgres that articles may be stemmed with
+	if config.SearchLanguage != "indonesian" && config.SearchLanguage != "english" {
+		log.Printf("⚠️  Warning: Unsupported SEARCH_LANGUAGE %q, using indonesian.", config.SearchLanguage)
+		config.SearchLanguage = "indonesian"
+	}
+
+	if config.AnalyticsBatchSize <= 0 {
+		config.AnalyticsBatchSize = 500
+	}
+	if config.AnalyticsBeaconRateWindow <= 0 {
+		config.AnalyticsBeaconRateWindow = time.Minute
+	}
+	// Rollups recompute the last days from raw events, so those must be kept.
+	if config.AnalyticsEventRetention < 48*time.Hour {
+		config.AnalyticsEventRetention = 48 * time.Hour
+	}
+	if config.AnalyticsHourlyRetention < 48*time.Hour {
+		config.AnalyticsHourlyRetention = 48 * time.Hour
+	}
+
+	config.UploadBaseURL = getEnv("UPLOAD_BASE_URL", config.BaseURL+"/uploads")
+	if config.MaxUploadSize <= 0 {
+		config.MaxUploadSize = 5 << 20
 	}
 
 	if config.DatabaseURL == "" {
@@ -54,6 +201,13 @@ func LoadConfig() *Config {
 		log.Fatal("❌ JWT_SECRET is not set")
 	}
 
+	// formats uploads can be re-encoded to; Go has no WebP encoder
+	switch config.ImageOutputFormat {
+	case "original", "jpeg", "png":
+	default:
+		log.Fatalf("❌ Unsupported IMAGE_OUTPUT_FORMAT %q, use original, jpeg or png", config.ImageOutputFormat)
+	}
+
 	if config.SMTPHost == "" || config.SMTPUser == "" || config.SMTPPass == "" || config.BaseURL == "" || config.FrontendURL == "" {
 		log.Fatal("❌ SMTP configuration is incomplete")
 	}
@@ -68,6 +222,31 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getListEnv reads a comma separated list, dropping empty entries.
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("⚠️  Warning: Invalid %s, using %s.", key, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
 func InitDB(cfg *Config) *gorm.DB {
 	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{})
 	if err != nil {
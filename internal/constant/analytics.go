@@ -1 +1,30 @@
 package constant
+
+// Heartbeats credit at most this many seconds of reading each, however
+// long the client claims the reader stayed.
+const AnalyticsMaxHeartbeatSeconds = 60
+
+// A reader with no hit for this long is treated as gone; a heartbeat after
+// it starts nothing and is ignored.
+const AnalyticsVisitTimeoutMinutes = 30
+
+// The visits and client IPs tracked in memory by the beacon are capped at
+// this many each; past it new ones go untracked until old ones expire.
+const AnalyticsMaxTrackedKeys = 100_000
+
+// A hit on an article missing from the cached published IDs reloads them,
+// but no more often than this.
+const AnalyticsArticleRefreshSeconds = 30
+
+// Hits reach the raw events up to a flush interval late, so every rollup
+// run recomputes from at least this long before the latest bucket.
+const AnalyticsRollupLagMinutes = 60
+
+// Longest ranges the dashboard reports on, per interval.
+const (
+	AnalyticsMaxHourlyRangeDays = 31
+	AnalyticsMaxDailyRangeDays  = 731
+)
+
+// AnalyticsDirectReferrer names the views that arrived without a referrer.
+const AnalyticsDirectReferrer = "(direct)"
@@ -0,0 +1,15 @@
+package constant
+
+// Sort fields accepted by article listings.
+const (
+	SortPublishedAt = "published_at"
+	SortCreatedAt   = "created_at"
+	SortUpdatedAt   = "updated_at"
+	SortTitle       = "title"
+)
+
+// Sort orders accepted by article listings.
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
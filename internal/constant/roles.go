@@ -1 +1,58 @@
 package constant
+
+import "github.com/tsaqiffatih/minddrift-server/internal/model"
+
+type Permission string
+
+const (
+	PermArticleCreate    Permission = "article:create"
+	PermArticleManageAny Permission = "article:manage_any" // edit/delete articles of other authors
+	PermArticleReview    Permission = "article:review"     // read unpublished articles, review queue
+	PermArticlePublish   Permission = "article:publish"    // approve, schedule, reject, unpublish
+	PermCommentModerate  Permission = "comment:moderate"
+	PermCategoryManage   Permission = "category:manage"
+	PermTagManage        Permission = "tag:manage"
+	PermUserManage       Permission = "user:manage"
+	PermAnalyticsViewAny Permission = "analytics:view_any" // analytics of other authors and the whole site
+	PermBackupCreate     Permission = "backup:create"
+	PermBackupRestore    Permission = "backup:restore"
+)
+
+// rolePermissions is the single source of truth for what each role may do.
+var rolePermissions = map[model.UserRole][]Permission{
+	model.Admin: {
+		PermArticleCreate,
+		PermArticleManageAny,
+		PermArticleReview,
+		PermArticlePublish,
+		PermCommentModerate,
+		PermCategoryManage,
+		PermTagManage,
+		PermUserManage,
+		PermAnalyticsViewAny,
+		PermBackupCreate,
+		PermBackupRestore,
+	},
+	model.Editor: {
+		PermArticleCreate,
+		PermArticleManageAny,
+		PermArticleReview,
+		PermArticlePublish,
+		PermCommentModerate,
+		PermCategoryManage,
+		PermTagManage,
+		PermAnalyticsViewAny,
+	},
+	model.Penulis: {
+		PermArticleCreate,
+	},
+}
+
+func HasPermission(role model.UserRole, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
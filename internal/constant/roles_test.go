@@ -0,0 +1,64 @@
+package constant
+
+import (
+	"testing"
+
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+)
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		permission Permission
+		admin      bool
+		editor     bool
+		penulis    bool
+	}{
+		{PermArticleCreate, true, true, true},
+		{PermArticleManageAny, true, true, false},
+		{PermArticleReview, true, true, false},
+		{PermArticlePublish, true, true, false},
+		{PermCommentModerate, true, true, false},
+		{PermCategoryManage, true, true, false},
+		{PermTagManage, true, true, false},
+		{PermUserManage, true, false, false},
+		{PermAnalyticsViewAny, true, true, false},
+		{PermBackupCreate, true, false, false},
+		{PermBackupRestore, true, false, false},
+		{Permission("article:unknown"), false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.permission), func(t *testing.T) {
+			for role, want := range map[model.UserRole]bool{
+				model.Admin:   tt.admin,
+				model.Editor:  tt.editor,
+				model.Penulis: tt.penulis,
+				"":            false,
+				"guest":       false,
+			} {
+				if got := HasPermission(role, tt.permission); got != want {
+					t.Errorf("HasPermission(%q, %q) = %v, want %v", role, tt.permission, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestRolePermissionsCovered fails when a role is given a permission the
+// matrix above does not pin down.
+func TestRolePermissionsCovered(t *testing.T) {
+	known := map[Permission]bool{
+		PermArticleCreate: true, PermArticleManageAny: true, PermArticleReview: true,
+		PermArticlePublish: true, PermCommentModerate: true, PermCategoryManage: true,
+		PermTagManage: true, PermUserManage: true, PermAnalyticsViewAny: true,
+		PermBackupCreate: true, PermBackupRestore: true,
+	}
+
+	for role, permissions := range rolePermissions {
+		for _, permission := range permissions {
+			if !known[permission] {
+				t.Errorf("role %q has untested permission %q", role, permission)
+			}
+		}
+	}
+}
@@ -1 +1,14 @@
 package constant
+
+// Feed formats served under /feeds.
+const (
+	FeedRSS  = "rss"
+	FeedAtom = "atom"
+	FeedJSON = "json"
+)
+
+// Feed scopes; the site feed has no scope.
+const (
+	FeedScopeCategory = "category"
+	FeedScopeTag      = "tag"
+)
@@ -0,0 +1,120 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalyticsBeaconRequest is sent by navigator.sendBeacon, which posts it
+// as text/plain. URL is the page the reader is on and carries the UTM
+// parameters; Seconds is only read on heartbeats.
+type AnalyticsBeaconRequest struct {
+	Type      string    `json:"type" validate:"required,oneof=view heartbeat"`
+	ArticleID uuid.UUID `json:"article_id" validate:"required"`
+	Seconds   int       `json:"seconds" validate:"min=0,max=3600"`
+	Referrer  string    `json:"referrer" validate:"max=2048"`
+	URL       string    `json:"url" validate:"max=2048"`
+}
+
+// AnalyticsDashboardRequest is read from the query string. Range picks a
+// preset ending now; From and To, formatted as in article listings, pick a
+// custom range instead. AuthorID and the site-wide view are only open to
+// users who may view any analytics.
+type AnalyticsDashboardRequest struct {
+	Range     string `validate:"omitempty,oneof=24h 7d 30d 90d 12m"`
+	From      string
+	To        string
+	Interval  string `validate:"omitempty,oneof=hour day"`
+	AuthorID  string `validate:"omitempty,uuid"`
+	ArticleID string `validate:"omitempty,uuid"`
+	Sort      string `validate:"omitempty,oneof=views unique_visitors read_time"`
+	Limit     int
+}
+
+// AnalyticsRangeResponse is the range a report covers after it was aligned
+// to whole buckets; To is exclusive.
+type AnalyticsRangeResponse struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Interval string    `json:"interval"`
+}
+
+// AnalyticsTotalsResponse gives read times in seconds. Unique visitors are
+// counted per hour or per day, depending on the interval, and summed.
+type AnalyticsTotalsResponse struct {
+	Views           int64 `json:"views"`
+	UniqueVisitors  int64 `json:"unique_visitors"`
+	TotalReadTime   int64 `json:"total_read_time"`
+	AverageReadTime int64 `json:"average_read_time"`
+}
+
+// AnalyticsOverviewResponse compares the range with the one just before
+// it. ViewsChange is a percentage, null when the previous range had no views.
+type AnalyticsOverviewResponse struct {
+	Range       AnalyticsRangeResponse  `json:"range"`
+	Current     AnalyticsTotalsResponse `json:"current"`
+	Previous    AnalyticsTotalsResponse `json:"previous"`
+	ViewsChange *float64                `json:"views_change"`
+}
+
+type AnalyticsTrendPoint struct {
+	Start           time.Time `json:"start"`
+	Views           int64     `json:"views"`
+	UniqueVisitors  int64     `json:"unique_visitors"`
+	AverageReadTime int64     `json:"average_read_time"`
+}
+
+type AnalyticsTrendResponse struct {
+	Range  AnalyticsRangeResponse `json:"range"`
+	Points []AnalyticsTrendPoint  `json:"points"`
+}
+
+type AnalyticsTopArticle struct {
+	ArticleID       uuid.UUID `json:"article_id"`
+	Title           string    `json:"title"`
+	Slug            string    `json:"slug"`
+	URL             string    `json:"url"`
+	Views           int64     `json:"views"`
+	UniqueVisitors  int64     `json:"unique_visitors"`
+	AverageReadTime int64     `json:"average_read_time"`
+
+	Channels []AnalyticsChannelCount `json:"channels"`
+}
+
+type AnalyticsTopArticlesResponse struct {
+	Range    AnalyticsRangeResponse `json:"range"`
+	Articles []AnalyticsTopArticle  `json:"articles"`
+}
+
+type AnalyticsSourceCount struct {
+	Name  string `json:"name"`
+	Views int64  `json:"views"`
+}
+
+// AnalyticsChannelCount counts views per channel: direct, search, social,
+// email, internal or referral.
+type AnalyticsChannelCount struct {
+	Channel string `json:"channel"`
+	Views   int64  `json:"views"`
+}
+
+// AnalyticsChannelSource names the search engine, network, mail provider
+// or referring site behind the views of a channel.
+type AnalyticsChannelSource struct {
+	Channel string `json:"channel"`
+	Name    string `json:"name"`
+	Views   int64  `json:"views"`
+}
+
+// AnalyticsSourcesResponse counts views over the whole UTC days the range
+// touches, since traffic sources are only kept per day.
+type AnalyticsSourcesResponse struct {
+	Range        AnalyticsRangeResponse   `json:"range"`
+	Channels     []AnalyticsChannelCount  `json:"channels"`
+	Sources      []AnalyticsChannelSource `json:"sources"`
+	Referrers    []AnalyticsSourceCount   `json:"referrers"`
+	UTMSources   []AnalyticsSourceCount   `json:"utm_sources"`
+	UTMMediums   []AnalyticsSourceCount   `json:"utm_mediums"`
+	UTMCampaigns []AnalyticsSourceCount   `json:"utm_campaigns"`
+}
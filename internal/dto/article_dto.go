@@ -1 +1,91 @@
 package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type CreateArticleRequest struct {
+	Title   string `json:"title" validate:"required,min=3,max=255"`
+	Content string `json:"content" validate:"required"`
+	Slug    string `json:"slug" validate:"omitempty,max=255"`
+}
+
+type UpdateArticleRequest struct {
+	Title   *string `json:"title,omitempty" validate:"omitempty,min=3,max=255"`
+	Content *string `json:"content,omitempty" validate:"omitempty,min=1"`
+	Slug    *string `json:"slug,omitempty" validate:"omitempty,max=255"`
+}
+
+type ArticleStatusRequest struct {
+	Status      string     `json:"status" validate:"required,oneof=draft review published scheduled"`
+	Reason      string     `json:"reason" validate:"max=1000"`
+	ScheduledAt *time.Time `json:"scheduled_at"`
+}
+
+type ArticleStatusHistoryResponse struct {
+	ID         uuid.UUID      `json:"id"`
+	FromStatus string         `json:"from_status"`
+	ToStatus   string         `json:"to_status"`
+	Actor      AuthorResponse `json:"actor"`
+	Reason     string         `json:"reason,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type AuthorResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
+
+// ArticleListRequest is read from the query string of article listings.
+// From and To accept a date (YYYY-MM-DD) or an RFC 3339 timestamp.
+type ArticleListRequest struct {
+	Status     string `validate:"omitempty,oneof=draft review published scheduled"`
+	AuthorID   string `validate:"omitempty,uuid"`
+	CategoryID string `validate:"omitempty,uuid"`
+	TagID      string `validate:"omitempty,uuid"`
+	From       string
+	To         string
+	Sort       string `validate:"omitempty,oneof=published_at created_at updated_at title"`
+	Order      string `validate:"omitempty,oneof=asc desc"`
+	Cursor     string `validate:"max=512"`
+	Limit      int
+}
+
+type ArticleResponse struct {
+	ID          uuid.UUID      `json:"id"`
+	Title       string         `json:"title"`
+	Content     string         `json:"content"`
+	Slug        string         `json:"slug"`
+	Status      string         `json:"status"`
+	Author      AuthorResponse `json:"author"`
+	PublishedAt *time.Time     `json:"published_at"`
+	ScheduledAt *time.Time     `json:"scheduled_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+type ArticleVersionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Version   int       `json:"version"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArticleDiffSide describes one side of a diff. ID is nil and Version is 0
+// when the side refers to the current article content.
+type ArticleDiffSide struct {
+	ID      *uuid.UUID `json:"id"`
+	Version int        `json:"version"`
+	Title   string     `json:"title"`
+}
+
+type ArticleDiffResponse struct {
+	From         ArticleDiffSide  `json:"from"`
+	To           ArticleDiffSide  `json:"to"`
+	TitleChanged bool             `json:"title_changed"`
+	Lines        []utils.DiffLine `json:"lines"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateCategoryRequest struct {
+	Name        string     `json:"name" validate:"required,min=2,max=100"`
+	Slug        string     `json:"slug" validate:"omitempty,max=120"`
+	Description string     `json:"description" validate:"max=1000"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+}
+
+// UpdateCategoryRequest changes only the fields that are sent. An empty
+// parent_id moves the category to the top level.
+type UpdateCategoryRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Slug        *string `json:"slug,omitempty" validate:"omitempty,max=120"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	ParentID    *string `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+}
+
+type CategoryBreadcrumb struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
+}
+
+// CategoryResponse counts published articles: ArticleCount those filed
+// directly under the category, TotalArticleCount those anywhere below it.
+type CategoryResponse struct {
+	ID                uuid.UUID            `json:"id"`
+	Name              string               `json:"name"`
+	Slug              string               `json:"slug"`
+	Description       string               `json:"description"`
+	ParentID          *uuid.UUID           `json:"parent_id"`
+	ArticleCount      int64                `json:"article_count"`
+	TotalArticleCount int64                `json:"total_article_count"`
+	Breadcrumbs       []CategoryBreadcrumb `json:"breadcrumbs,omitempty"`
+	Children          []CategoryResponse   `json:"children,omitempty"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+type ArticleCategoriesRequest struct {
+	CategoryIDs []uuid.UUID `json:"category_ids" validate:"max=10"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateCommentRequest struct {
+	Content    string     `json:"content" validate:"required,min=1,max=5000"`
+	ParentID   *uuid.UUID `json:"parent_id"`
+	GuestName  string     `json:"guest_name" validate:"omitempty,min=2,max=100"`
+	GuestEmail string     `json:"guest_email" validate:"omitempty,email,max=255"`
+	Website    string     `json:"website"` // honeypot, hidden from humans
+}
+
+type CommentStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending approved spam rejected"`
+}
+
+type CommentResponse struct {
+	ID            uuid.UUID         `json:"id"`
+	ArticleID     uuid.UUID         `json:"article_id"`
+	ParentID      *uuid.UUID        `json:"parent_id"`
+	Content       string            `json:"content"`
+	Status        string            `json:"status"`
+	Author        AuthorResponse    `json:"author"`
+	IsAuthorReply bool              `json:"is_author_reply"`
+	IsGuest       bool              `json:"is_guest"`
+	CreatedAt     time.Time         `json:"created_at"`
+	Replies       []CommentResponse `json:"replies,omitempty"`
+}
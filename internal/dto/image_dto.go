@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UploadImageRequest struct {
+	AltText   string `form:"alt_text" validate:"max=255"`
+	Caption   string `form:"caption" validate:"max=500"`
+	ArticleID string `form:"article_id" validate:"omitempty,uuid"`
+}
+
+type RemoteImageRequest struct {
+	URL       string     `json:"url" validate:"required,url,max=2048"`
+	AltText   string     `json:"alt_text" validate:"max=255"`
+	Caption   string     `json:"caption" validate:"max=500"`
+	ArticleID *uuid.UUID `json:"article_id"`
+}
+
+type UpdateImageRequest struct {
+	AltText *string `json:"alt_text" validate:"omitempty,max=255"`
+	Caption *string `json:"caption" validate:"omitempty,max=500"`
+}
+
+type ImageVariantResponse struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int64  `json:"size"`
+}
+
+type ImageResponse struct {
+	ID         uuid.UUID              `json:"id"`
+	URL        string                 `json:"url"`
+	MimeType   string                 `json:"mime_type"`
+	Size       int64                  `json:"size"`
+	Width      int                    `json:"width"`
+	Height     int                    `json:"height"`
+	AltText    string                 `json:"alt_text"`
+	Caption    string                 `json:"caption"`
+	ArticleID  *uuid.UUID             `json:"article_id"`
+	UploadedBy uuid.UUID              `json:"uploaded_by"`
+	Variants   []ImageVariantResponse `json:"variants"`
+	Srcset     string                 `json:"srcset"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
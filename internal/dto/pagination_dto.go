@@ -0,0 +1,49 @@
+package dto
+
+// PageResponse is the envelope shared by paginated list endpoints. Cursor
+// paginated lists fill NextCursor; offset paginated lists fill Page and
+// Total.
+type PageResponse[T any] struct {
+	Items      []T            `json:"items"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+type PaginationMeta struct {
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// NewCursorPage wraps one page of a cursor paginated list; an empty
+// nextCursor marks the last page.
+func NewCursorPage[T any](items []T, limit int, nextCursor string) PageResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return PageResponse[T]{
+		Items: items,
+		Pagination: PaginationMeta{
+			Limit:      limit,
+			HasMore:    nextCursor != "",
+			NextCursor: nextCursor,
+		},
+	}
+}
+
+// NewOffsetPage wraps one page of an offset paginated list.
+func NewOffsetPage[T any](items []T, page, limit int, total int64) PageResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return PageResponse[T]{
+		Items: items,
+		Pagination: PaginationMeta{
+			Limit:   limit,
+			HasMore: int64(page)*int64(limit) < total,
+			Page:    page,
+			Total:   &total,
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArticleSearchRequest is read from the query string. From and To accept a
+// date (YYYY-MM-DD) or an RFC 3339 timestamp; a date in To covers the
+// whole day.
+type ArticleSearchRequest struct {
+	Query      string `validate:"required,min=2,max=200"`
+	CategoryID string `validate:"omitempty,uuid"`
+	TagID      string `validate:"omitempty,uuid"`
+	AuthorID   string `validate:"omitempty,uuid"`
+	From       string
+	To         string
+	Page       int
+	Limit      int
+}
+
+// ArticleSearchResult carries HTML-escaped highlights: matched words are
+// wrapped in <mark> and nothing else in them is markup.
+type ArticleSearchResult struct {
+	ID             uuid.UUID      `json:"id"`
+	Title          string         `json:"title"`
+	TitleHighlight string         `json:"title_highlight"`
+	Snippet        string         `json:"snippet"`
+	Slug           string         `json:"slug"`
+	URL            string         `json:"url"`
+	Author         AuthorResponse `json:"author"`
+	PublishedAt    *time.Time     `json:"published_at"`
+	Rank           float64        `json:"rank"`
+}
@@ -0,0 +1,74 @@
+package dto
+
+import "github.com/google/uuid"
+
+type SEOMetadataRequest struct {
+	MetaTitle       string   `json:"meta_title" validate:"max=255"`
+	MetaDescription string   `json:"meta_description" validate:"max=1000"`
+	Keywords        []string `json:"keywords" validate:"max=20,dive,min=1,max=50"`
+}
+
+// SEOMetadataResponse holds the metadata in effect for an article. Fields
+// the author left empty are generated from the article and flagged as such.
+type SEOMetadataResponse struct {
+	ArticleID            uuid.UUID `json:"article_id"`
+	MetaTitle            string    `json:"meta_title"`
+	MetaDescription      string    `json:"meta_description"`
+	Keywords             []string  `json:"keywords"`
+	TitleGenerated       bool      `json:"title_generated"`
+	DescriptionGenerated bool      `json:"description_generated"`
+}
+
+// SEOPreviewResponse approximates how the article shows up in a search
+// engine result page.
+type SEOPreviewResponse struct {
+	Title                string   `json:"title"`
+	Description          string   `json:"description"`
+	URL                  string   `json:"url"`
+	DisplayURL           string   `json:"display_url"`
+	Breadcrumbs          []string `json:"breadcrumbs"`
+	TitleTruncated       bool     `json:"title_truncated"`
+	DescriptionTruncated bool     `json:"description_truncated"`
+	TitleLength          int      `json:"title_length"`
+	DescriptionLength    int      `json:"description_length"`
+}
+
+type SEOCheckResult struct {
+	ID         string `json:"id"`
+	Label      string `json:"label"`
+	Score      int    `json:"score"`
+	MaxScore   int    `json:"max_score"`
+	Passed     bool   `json:"passed"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+type SEOScoreResponse struct {
+	ArticleID      uuid.UUID        `json:"article_id"`
+	Keyword        string           `json:"keyword"`
+	Score          int              `json:"score"`
+	WordCount      int              `json:"word_count"`
+	KeywordDensity float64          `json:"keyword_density"`
+	InternalLinks  int              `json:"internal_links"`
+	ExternalLinks  int              `json:"external_links"`
+	Checks         []SEOCheckResult `json:"checks"`
+	Suggestions    []string         `json:"suggestions"`
+}
+
+// MetaTag is one <meta> element; Attribute is "property" for Open Graph
+// and "name" for Twitter Cards.
+type MetaTag struct {
+	Attribute string `json:"attribute"`
+	Key       string `json:"key"`
+	Content   string `json:"content"`
+}
+
+// SocialMetadataResponse holds the tags for sharing an article, plus the
+// same data rendered as an HTML fragment for the document head.
+type SocialMetadataResponse struct {
+	ArticleID   uuid.UUID      `json:"article_id"`
+	OpenGraph   []MetaTag      `json:"open_graph"`
+	TwitterCard []MetaTag      `json:"twitter_card"`
+	JSONLD      map[string]any `json:"json_ld"`
+	Head        string         `json:"head"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=50"`
+	Slug string `json:"slug" validate:"omitempty,max=120"`
+}
+
+type MergeTagsRequest struct {
+	SourceIDs []uuid.UUID `json:"source_ids" validate:"required,min=1,max=50"`
+}
+
+type TagResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Slug         string    `json:"slug"`
+	ArticleCount int64     `json:"article_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ArticleTagsRequest sets tags by ID, by name, or both. Names are matched
+// against existing tags after normalization, so "golang" picks the "Go" tag.
+type ArticleTagsRequest struct {
+	TagIDs   []uuid.UUID `json:"tag_ids" validate:"max=20"`
+	TagNames []string    `json:"tag_names" validate:"max=20,dive,min=1,max=50"`
+}
+
+// TagNearMatch lists existing tags one typo away from a name that was
+// created or not found, so the client can offer "did you mean".
+type TagNearMatch struct {
+	Name       string   `json:"name"`
+	DidYouMean []string `json:"did_you_mean"`
+}
+
+type TagSuggestionResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Slug         string    `json:"slug"`
+	Score        float64   `json:"score"`
+	MatchedTerms []string  `json:"matched_terms"`
+}
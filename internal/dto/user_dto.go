@@ -48,9 +48,31 @@ type UserResponse struct {
 	TwoFAEnabled  bool      `json:"two_fa_enabled"`
 }
 
+// LoginResponse either carries a session or, when the user has 2FA
+// enabled, only TwoFARequired and the ChallengeToken for the second step.
 type LoginResponse struct {
-	Token        string       `json:"token"`
-	RefreshToken string       `json:"refresh_token"`
-	ExpiresAt    time.Time    `json:"expires_at"`
-	User         UserResponse `json:"user"`
+	Token          string        `json:"token,omitempty"`
+	RefreshToken   string        `json:"refresh_token,omitempty"`
+	ExpiresAt      *time.Time    `json:"expires_at,omitempty"`
+	User           *UserResponse `json:"user,omitempty"`
+	TwoFARequired  bool          `json:"two_fa_required"`
+	ChallengeToken string        `json:"challenge_token,omitempty"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type TwoFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFALoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type TwoFASetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
 }
@@ -1 +1,156 @@
 package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type AnalyticsHandler interface {
+	RecordBeacon(c *gin.Context)
+	GetOverview(c *gin.Context)
+	GetTrend(c *gin.Context)
+	GetTopArticles(c *gin.Context)
+	GetTrafficSources(c *gin.Context)
+}
+
+type analyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) AnalyticsHandler {
+	return &analyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// **Record Beacon**
+// Accepts the JSON body whatever its content type, since sendBeacon posts
+// text/plain. Answers 204 with no body, for bots too.
+func (h *analyticsHandler) RecordBeacon(c *gin.Context) {
+	var req dto.AnalyticsBeaconRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	err := h.analyticsService.RecordBeacon(service.BeaconInput{
+		Type:      model.AnalyticsEventType(req.Type),
+		ArticleID: req.ArticleID,
+		Seconds:   req.Seconds,
+		Referrer:  req.Referrer,
+		PageURL:   req.URL,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+	})
+	if errors.Is(err, model.ErrAnalyticsRateLimited) {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// **Analytics Overview**
+func (h *analyticsHandler) GetOverview(c *gin.Context) {
+	respondAnalyticsReport(c, h.analyticsService.GetOverview)
+}
+
+// **Analytics Trend**
+func (h *analyticsHandler) GetTrend(c *gin.Context) {
+	respondAnalyticsReport(c, h.analyticsService.GetTrend)
+}
+
+// **Top Articles**
+func (h *analyticsHandler) GetTopArticles(c *gin.Context) {
+	respondAnalyticsReport(c, h.analyticsService.GetTopArticles)
+}
+
+// **Traffic Sources**
+func (h *analyticsHandler) GetTrafficSources(c *gin.Context) {
+	respondAnalyticsReport(c, h.analyticsService.GetTrafficSources)
+}
+
+// respondAnalyticsReport reads the range and filters shared by the
+// dashboard endpoints, runs the report and writes its result.
+func respondAnalyticsReport[T any](c *gin.Context, report func(dto.AnalyticsDashboardRequest, uuid.UUID, model.UserRole) (*T, error)) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	req := dto.AnalyticsDashboardRequest{
+		Range:     c.Query("range"),
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+		Interval:  c.Query("interval"),
+		AuthorID:  c.Query("author_id"),
+		ArticleID: c.Query("article_id"),
+		Sort:      c.Query("sort"),
+		Limit:     parseLimitQuery(c, 10),
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	result, err := report(req, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondAnalyticsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+func respondAnalyticsError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrDateFilterInvalid),
+		errors.Is(err, model.ErrDateFilterRange),
+		errors.Is(err, model.ErrAnalyticsFromRequired),
+		errors.Is(err, model.ErrAnalyticsRangeTooLong):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrAnalyticsForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "errors": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "errors": err.Error()})
+	}
+}
@@ -1 +1,397 @@
 package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type ArticleHandler interface {
+	CreateArticle(c *gin.Context)
+	GetAllArticles(c *gin.Context)
+	GetMyArticles(c *gin.Context)
+	GetArticleByID(c *gin.Context)
+	GetArticleBySlug(c *gin.Context)
+	UpdateArticle(c *gin.Context)
+	DeleteArticle(c *gin.Context)
+
+	GetArticleVersions(c *gin.Context)
+	DiffArticleVersions(c *gin.Context)
+	RestoreArticleVersion(c *gin.Context)
+}
+
+type articleHandler struct {
+	articleService service.ArticleService
+}
+
+func NewArticleHandler(articleService service.ArticleService) ArticleHandler {
+	return &articleHandler{
+		articleService: articleService,
+	}
+}
+
+// **Create Article**
+func (h *articleHandler) CreateArticle(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	var req dto.CreateArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	article := &model.Article{
+		Title:    req.Title,
+		Content:  req.Content,
+		Slug:     req.Slug,
+		AuthorID: userID,
+	}
+
+	createdArticle, err := h.articleService.CreateArticle(article)
+	if err != nil {
+		respondArticleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Article created successfully",
+		"data":    gin.H{"article": toArticleResponse(createdArticle)},
+	})
+}
+
+// **Get Articles**
+// Published articles by default; see dto.ArticleListRequest for filters.
+func (h *articleHandler) GetAllArticles(c *gin.Context) {
+	req, ok := bindArticleListRequest(c)
+	if !ok {
+		return
+	}
+
+	articles, nextCursor, err := h.articleService.ListArticles(req, middleware.GetUserRole(c))
+	if err != nil {
+		respondArticleListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dto.NewCursorPage(toArticleResponses(articles), req.Limit, nextCursor),
+	})
+}
+
+// **Get Articles Of The Current User**
+func (h *articleHandler) GetMyArticles(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	req, ok := bindArticleListRequest(c)
+	if !ok {
+		return
+	}
+
+	articles, nextCursor, err := h.articleService.ListAuthorArticles(userID, req)
+	if err != nil {
+		respondArticleListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dto.NewCursorPage(toArticleResponses(articles), req.Limit, nextCursor),
+	})
+}
+
+// **Get Article By ID**
+func (h *articleHandler) GetArticleByID(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	article, err := h.articleService.GetArticleByID(articleID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondArticleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"article": toArticleResponse(article)},
+	})
+}
+
+// **Get Article By Slug**
+// Old slugs answer with a 301 to the article's current slug.
+func (h *articleHandler) GetArticleBySlug(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	article, redirectSlug, err := h.articleService.GetArticleBySlug(c.Param("slug"), userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondArticleError(c, err)
+		return
+	}
+
+	if redirectSlug != "" {
+		c.Redirect(http.StatusMovedPermanently, "/api/articles/slug/"+url.PathEscape(redirectSlug))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"article": toArticleResponse(article)},
+	})
+}
+
+// **Update Article**
+func (h *articleHandler) UpdateArticle(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	var req dto.UpdateArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	updatedArticle, err := h.articleService.UpdateArticle(articleID, userID, middleware.GetUserRole(c), req)
+	if err != nil {
+		respondArticleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Article updated successfully",
+		"data":    gin.H{"article": toArticleResponse(updatedArticle)},
+	})
+}
+
+// **Delete Article**
+func (h *articleHandler) DeleteArticle(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	if err := h.articleService.DeleteArticle(articleID, userID, middleware.GetUserRole(c)); err != nil {
+		respondArticleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Article deleted successfully",
+	})
+}
+
+// **Get Article Versions**
+func (h *articleHandler) GetArticleVersions(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	versions, err := h.articleService.ListArticleVersions(articleID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondArticleError(c, err)
+		return
+	}
+
+	responses := make([]dto.ArticleVersionResponse, 0, len(versions))
+	for _, version := range versions {
+		responses = append(responses, dto.ArticleVersionResponse{
+			ID:        version.ID,
+			Version:   version.Version,
+			Title:     version.Title,
+			Content:   version.Content,
+			CreatedAt: version.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"versions": responses},
+	})
+}
+
+// **Diff Article Versions**
+// Query params `from` and `to` take a version ID or "current" (the default).
+func (h *articleHandler) DiffArticleVersions(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	fromID, err := parseVersionParam(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid from version ID"})
+		return
+	}
+
+	toID, err := parseVersionParam(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid to version ID"})
+		return
+	}
+
+	diff, err := h.articleService.DiffArticleVersions(articleID, userID, middleware.GetUserRole(c), fromID, toID)
+	if err != nil {
+		respondArticleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"diff": diff},
+	})
+}
+
+// **Restore Article Version**
+func (h *articleHandler) RestoreArticleVersion(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	versionID, err := uuid.Parse(c.Param("versionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid version ID"})
+		return
+	}
+
+	article, err := h.articleService.RestoreArticleVersion(articleID, userID, middleware.GetUserRole(c), versionID)
+	if err != nil {
+		respondArticleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Article version restored successfully",
+		"data":    gin.H{"article": toArticleResponse(article)},
+	})
+}
+
+func parseVersionParam(value string) (uuid.UUID, error) {
+	if value == "" || value == "current" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(value)
+}
+
+func respondArticleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrArticleNotFound), errors.Is(err, model.ErrArticleVersionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrArticleForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrSlugAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "errors": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "errors": err.Error()})
+	}
+}
+
+func toArticleResponse(article *model.Article) dto.ArticleResponse {
+	return dto.ArticleResponse{
+		ID:      article.ID,
+		Title:   article.Title,
+		Content: article.Content,
+		Slug:    article.Slug,
+		Status:  string(article.Status),
+		Author: dto.AuthorResponse{
+			ID:       article.AuthorID,
+			Username: article.Author.Username,
+		},
+		PublishedAt: article.PublishedAt,
+		ScheduledAt: article.ScheduledAt,
+		CreatedAt:   article.CreatedAt,
+		UpdatedAt:   article.UpdatedAt,
+	}
+}
+
+func toArticleResponses(articles []model.Article) []dto.ArticleResponse {
+	responses := make([]dto.ArticleResponse, 0, len(articles))
+	for i := range articles {
+		responses = append(responses, toArticleResponse(&articles[i]))
+	}
+	return responses
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type ArticleWorkflowHandler interface {
+	ChangeArticleStatus(c *gin.Context)
+	GetStatusHistory(c *gin.Context)
+	GetReviewQueue(c *gin.Context)
+}
+
+type articleWorkflowHandler struct {
+	workflowService service.ArticleWorkflowService
+}
+
+func NewArticleWorkflowHandler(workflowService service.ArticleWorkflowService) ArticleWorkflowHandler {
+	return &articleWorkflowHandler{
+		workflowService: workflowService,
+	}
+}
+
+// **Change Article Status**
+func (h *articleWorkflowHandler) ChangeArticleStatus(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	var req dto.ArticleStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	article, err := h.workflowService.TransitionArticle(
+		articleID,
+		userID,
+		middleware.GetUserRole(c),
+		model.ArticleStatus(req.Status),
+		req.Reason,
+		req.ScheduledAt,
+	)
+	if err != nil {
+		respondWorkflowError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Article status updated successfully",
+		"data":    gin.H{"article": toArticleResponse(article)},
+	})
+}
+
+// **Get Article Status History**
+func (h *articleWorkflowHandler) GetStatusHistory(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	histories, err := h.workflowService.GetStatusHistory(articleID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondWorkflowError(c, err)
+		return
+	}
+
+	responses := make([]dto.ArticleStatusHistoryResponse, 0, len(histories))
+	for _, history := range histories {
+		responses = append(responses, dto.ArticleStatusHistoryResponse{
+			ID:         history.ID,
+			FromStatus: string(history.FromStatus),
+			ToStatus:   string(history.ToStatus),
+			Actor: dto.AuthorResponse{
+				ID:       history.ActorID,
+				Username: history.Actor.Username,
+			},
+			Reason:    history.Reason,
+			CreatedAt: history.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"history": responses},
+	})
+}
+
+// **Get Review Queue**
+func (h *articleWorkflowHandler) GetReviewQueue(c *gin.Context) {
+	req, ok := bindArticleListRequest(c)
+	if !ok {
+		return
+	}
+
+	articles, nextCursor, err := h.workflowService.ListReviewQueue(req)
+	if err != nil {
+		respondArticleListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dto.NewCursorPage(toArticleResponses(articles), req.Limit, nextCursor),
+	})
+}
+
+func respondWorkflowError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrTransitionNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrInvalidStatusTransition),
+		errors.Is(err, model.ErrTransitionReasonMissing),
+		errors.Is(err, model.ErrInvalidScheduleTime):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrStatusChanged):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "errors": err.Error()})
+	default:
+		respondArticleError(c, err)
+	}
+}
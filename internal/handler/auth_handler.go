@@ -1 +1,125 @@
 package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type AuthHandler interface {
+	RefreshToken(c *gin.Context)
+	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
+}
+
+type authHandler struct {
+	authService service.AuthService
+}
+
+func NewAuthHandler(authService service.AuthService) AuthHandler {
+	return &authHandler{
+		authService: authService,
+	}
+}
+
+// **Refresh Token**
+func (h *authHandler) RefreshToken(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if !bindRefreshToken(c, &req) {
+		return
+	}
+
+	session, err := h.authService.RefreshSession(req.RefreshToken)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, model.ErrInvalidRefreshToken) || errors.Is(err, model.ErrRefreshTokenReused) {
+			status = http.StatusUnauthorized
+		}
+
+		c.JSON(status, gin.H{
+			"success": false,
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Token refreshed",
+		"data":    session,
+	})
+}
+
+// **Logout**
+func (h *authHandler) Logout(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if !bindRefreshToken(c, &req) {
+		return
+	}
+
+	if err := h.authService.Logout(req.RefreshToken); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, model.ErrInvalidRefreshToken) {
+			status = http.StatusUnauthorized
+		}
+
+		c.JSON(status, gin.H{
+			"success": false,
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logout success",
+	})
+}
+
+// **Logout All Sessions**
+func (h *authHandler) LogoutAll(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	if err := h.authService.LogoutAll(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"errors":  "Something went wrong, please try again later",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "All sessions have been logged out",
+	})
+}
+
+func bindRefreshToken(c *gin.Context, req *dto.RefreshTokenRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return false
+	}
+
+	if err := utils.ValidateStruct(*req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return false
+	}
+
+	return true
+}
@@ -1 +1,257 @@
 package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type CategoryHandler interface {
+	GetCategories(c *gin.Context)
+	GetCategoryByID(c *gin.Context)
+	GetCategoryBySlug(c *gin.Context)
+	CreateCategory(c *gin.Context)
+	UpdateCategory(c *gin.Context)
+	DeleteCategory(c *gin.Context)
+
+	GetArticleCategories(c *gin.Context)
+	SetArticleCategories(c *gin.Context)
+}
+
+type categoryHandler struct {
+	categoryService service.CategoryService
+}
+
+func NewCategoryHandler(categoryService service.CategoryService) CategoryHandler {
+	return &categoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// **Get Category Tree**
+func (h *categoryHandler) GetCategories(c *gin.Context) {
+	categories, err := h.categoryService.ListCategoryTree()
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"categories": categories},
+	})
+}
+
+// **Get Category By ID**
+func (h *categoryHandler) GetCategoryByID(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid category ID"})
+		return
+	}
+
+	category, err := h.categoryService.GetCategoryByID(categoryID)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"category": category},
+	})
+}
+
+// **Get Category By Slug**
+func (h *categoryHandler) GetCategoryBySlug(c *gin.Context) {
+	category, err := h.categoryService.GetCategoryBySlug(c.Param("slug"))
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"category": category},
+	})
+}
+
+// **Create Category**
+func (h *categoryHandler) CreateCategory(c *gin.Context) {
+	var req dto.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(req)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Category created successfully",
+		"data":    gin.H{"category": category},
+	})
+}
+
+// **Update Category**
+func (h *categoryHandler) UpdateCategory(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid category ID"})
+		return
+	}
+
+	var req dto.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(categoryID, req)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Category updated successfully",
+		"data":    gin.H{"category": category},
+	})
+}
+
+// **Delete Category**
+func (h *categoryHandler) DeleteCategory(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid category ID"})
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(categoryID); err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Category deleted successfully",
+	})
+}
+
+// **Get Article Categories**
+func (h *categoryHandler) GetArticleCategories(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	categories, err := h.categoryService.GetArticleCategories(articleID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"categories": categories},
+	})
+}
+
+// **Set Article Categories**
+func (h *categoryHandler) SetArticleCategories(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	var req dto.ArticleCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	categories, err := h.categoryService.SetArticleCategories(articleID, userID, middleware.GetUserRole(c), req.CategoryIDs)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Article categories updated successfully",
+		"data":    gin.H{"categories": categories},
+	})
+}
+
+func respondCategoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrCategoryNotFound),
+		errors.Is(err, model.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrArticleForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrCategoryNameExists),
+		errors.Is(err, model.ErrCategorySlugExists):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrParentCategoryMissing),
+		errors.Is(err, model.ErrCategoryCycle):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "errors": err.Error()})
+	}
+}
@@ -1 +1,295 @@
 package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type CommentHandler interface {
+	CreateComment(c *gin.Context)
+	ConfirmGuestComment(c *gin.Context)
+	GetArticleComments(c *gin.Context)
+	GetModerationQueue(c *gin.Context)
+	ModerateComment(c *gin.Context)
+	DeleteComment(c *gin.Context)
+}
+
+type commentHandler struct {
+	commentService service.CommentService
+}
+
+func NewCommentHandler(commentService service.CommentService) CommentHandler {
+	return &commentHandler{
+		commentService: commentService,
+	}
+}
+
+// **Create Comment**
+// Signed-in users comment under their account, guests with a name and email.
+func (h *commentHandler) CreateComment(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	input := service.CreateCommentInput{
+		ArticleID:  articleID,
+		Role:       middleware.GetUserRole(c),
+		Content:    req.Content,
+		ParentID:   req.ParentID,
+		GuestName:  req.GuestName,
+		GuestEmail: req.GuestEmail,
+		Honeypot:   req.Website,
+		IPAddress:  c.ClientIP(),
+	}
+	if userID, ok := middleware.GetUserID(c); ok {
+		input.UserID = &userID
+	}
+
+	comment, err := h.commentService.CreateComment(input)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	message := "Comment posted successfully"
+	switch {
+	case comment.ConfirmationHash != "":
+		message = "Please check your email to confirm your comment"
+	case comment.Status != model.CommentApproved:
+		message = "Comment submitted and is waiting for moderation"
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": message,
+		"data":    gin.H{"comment": toCommentResponse(comment)},
+	})
+}
+
+// **Confirm Guest Comment**
+func (h *commentHandler) ConfirmGuestComment(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Token is required"})
+		return
+	}
+
+	comment, err := h.commentService.ConfirmGuestComment(token)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	message := "Comment confirmed and published"
+	if comment.Status != model.CommentApproved {
+		message = "Comment confirmed and is waiting for moderation"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data":    gin.H{"comment": toCommentResponse(comment)},
+	})
+}
+
+// **Get Article Comments (Threaded)**
+func (h *commentHandler) GetArticleComments(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	page, limit := parsePageQuery(c, 10)
+
+	roots, replies, total, err := h.commentService.GetCommentTree(articleID, page, limit)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dto.NewOffsetPage(buildCommentTree(roots, replies), page, limit, total),
+	})
+}
+
+// **Get Moderation Queue**
+func (h *commentHandler) GetModerationQueue(c *gin.Context) {
+	page, limit := parsePageQuery(c, 20)
+	status := model.CommentStatus(c.DefaultQuery("status", string(model.CommentPending)))
+
+	comments, total, err := h.commentService.ListModerationQueue(status, page, limit)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	responses := make([]dto.CommentResponse, 0, len(comments))
+	for i := range comments {
+		responses = append(responses, toCommentResponse(&comments[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dto.NewOffsetPage(responses, page, limit, total),
+	})
+}
+
+// **Moderate Comment**
+func (h *commentHandler) ModerateComment(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid comment ID"})
+		return
+	}
+
+	var req dto.CommentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	if err := h.commentService.ModerateComment(commentID, userID, middleware.GetUserRole(c), model.CommentStatus(req.Status)); err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Comment status updated successfully",
+	})
+}
+
+// **Delete Comment**
+func (h *commentHandler) DeleteComment(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid comment ID"})
+		return
+	}
+
+	if err := h.commentService.DeleteComment(commentID, userID, middleware.GetUserRole(c)); err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Comment deleted successfully",
+	})
+}
+
+func respondCommentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrCommentNotFound), errors.Is(err, model.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrCommentForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrInvalidParent),
+		errors.Is(err, model.ErrGuestInfoMissing),
+		errors.Is(err, model.ErrInvalidActionToken):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "errors": err.Error()})
+	}
+}
+
+// buildCommentTree nests replies under their parents. Replies whose parent
+// is not in the set (e.g. still pending or deleted) are left out.
+func buildCommentTree(roots, replies []model.Comment) []dto.CommentResponse {
+	children := make(map[uuid.UUID][]*model.Comment)
+	for i := range replies {
+		reply := &replies[i]
+		if reply.ParentID != nil {
+			children[*reply.ParentID] = append(children[*reply.ParentID], reply)
+		}
+	}
+
+	var build func(comment *model.Comment) dto.CommentResponse
+	build = func(comment *model.Comment) dto.CommentResponse {
+		response := toCommentResponse(comment)
+		for _, child := range children[comment.ID] {
+			response.Replies = append(response.Replies, build(child))
+		}
+		return response
+	}
+
+	tree := make([]dto.CommentResponse, 0, len(roots))
+	for i := range roots {
+		tree = append(tree, build(&roots[i]))
+	}
+	return tree
+}
+
+func toCommentResponse(comment *model.Comment) dto.CommentResponse {
+	author := dto.AuthorResponse{Username: comment.GuestName}
+	if comment.UserID != nil {
+		author = dto.AuthorResponse{
+			ID:       *comment.UserID,
+			Username: comment.User.Username,
+		}
+	}
+
+	return dto.CommentResponse{
+		ID:            comment.ID,
+		ArticleID:     comment.ArticleID,
+		ParentID:      comment.ParentID,
+		Content:       comment.Content,
+		Status:        string(comment.Status),
+		Author:        author,
+		IsAuthorReply: comment.IsAuthorReply,
+		IsGuest:       comment.IsGuest(),
+		CreatedAt:     comment.CreatedAt,
+	}
+}
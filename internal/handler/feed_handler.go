@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+)
+
+// how long crawlers and feed readers may reuse a response without asking
+const feedCacheControl = "public, max-age=300"
+
+type FeedHandler interface {
+	GetSitemap(c *gin.Context)
+	GetSitemapPage(c *gin.Context)
+	GetRobots(c *gin.Context)
+	GetSiteFeed(c *gin.Context)
+	GetCategoryFeed(c *gin.Context)
+	GetTagFeed(c *gin.Context)
+}
+
+type feedHandler struct {
+	feedService service.FeedService
+}
+
+func NewFeedHandler(feedService service.FeedService) FeedHandler {
+	return &feedHandler{
+		feedService: feedService,
+	}
+}
+
+// **Get Sitemap**
+func (h *feedHandler) GetSitemap(c *gin.Context) {
+	document, err := h.feedService.Sitemap(0)
+	if err != nil {
+		respondFeedError(c, err)
+		return
+	}
+
+	serveFeedDocument(c, document)
+}
+
+// **Get Sitemap Page**
+// Pages are listed in the sitemap index as /sitemaps/<n>.xml.
+func (h *feedHandler) GetSitemapPage(c *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+	if err != nil || page < 1 {
+		respondFeedError(c, model.ErrSitemapPageNotFound)
+		return
+	}
+
+	document, err := h.feedService.Sitemap(page)
+	if err != nil {
+		respondFeedError(c, err)
+		return
+	}
+
+	serveFeedDocument(c, document)
+}
+
+// **Get Robots**
+func (h *feedHandler) GetRobots(c *gin.Context) {
+	serveFeedDocument(c, h.feedService.Robots())
+}
+
+// **Get Site Feed**
+func (h *feedHandler) GetSiteFeed(c *gin.Context) {
+	document, err := h.feedService.Feed(c.Param("format"), "", "")
+	if err != nil {
+		respondFeedError(c, err)
+		return
+	}
+
+	serveFeedDocument(c, document)
+}
+
+// **Get Category Feed**
+func (h *feedHandler) GetCategoryFeed(c *gin.Context) {
+	h.scopedFeed(c, constant.FeedScopeCategory)
+}
+
+// **Get Tag Feed**
+func (h *feedHandler) GetTagFeed(c *gin.Context) {
+	h.scopedFeed(c, constant.FeedScopeTag)
+}
+
+func (h *feedHandler) scopedFeed(c *gin.Context, scope string) {
+	document, err := h.feedService.Feed(c.Param("format"), scope, c.Param("slug"))
+	if err != nil {
+		respondFeedError(c, err)
+		return
+	}
+
+	serveFeedDocument(c, document)
+}
+
+// serveFeedDocument writes the document with an ETag and Last-Modified and
+// answers 304 when the client's copy is still current. If-None-Match wins
+// over If-Modified-Since, as RFC 9110 requires.
+func serveFeedDocument(c *gin.Context, document *dto.FeedDocument) {
+	sum := sha256.Sum256(document.Body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", feedCacheControl)
+	if !document.LastModified.IsZero() {
+		c.Header("Last-Modified", document.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if etagMatches(match, etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if since := c.GetHeader("If-Modified-Since"); since != "" && !document.LastModified.IsZero() {
+		// Last-Modified has second precision
+		if t, err := http.ParseTime(since); err == nil && !document.LastModified.Truncate(time.Second).After(t) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	c.Data(http.StatusOK, document.ContentType, document.Body)
+}
+
+// etagMatches compares weakly, so "W/" prefixes added by proxies that
+// compress the response still match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func respondFeedError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrSitemapPageNotFound),
+		errors.Is(err, model.ErrFeedNotFound),
+		errors.Is(err, model.ErrCategoryNotFound),
+		errors.Is(err, model.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "errors": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "errors": err.Error()})
+	}
+}
@@ -1 +1,385 @@
 package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+// room for the multipart boundaries and text fields around the file
+const multipartOverhead = 1 << 20
+
+type ImageHandler interface {
+	UploadImage(c *gin.Context)
+	UploadImageFromURL(c *gin.Context)
+	GetImageByID(c *gin.Context)
+	UpdateImage(c *gin.Context)
+	DeleteImage(c *gin.Context)
+	GetMyImages(c *gin.Context)
+	GetArticleImages(c *gin.Context)
+}
+
+type imageHandler struct {
+	imageService service.ImageService
+	cfg          *config.Config
+}
+
+func NewImageHandler(imageService service.ImageService, cfg *config.Config) ImageHandler {
+	return &imageHandler{
+		imageService: imageService,
+		cfg:          cfg,
+	}
+}
+
+// **Upload Image**
+// Expects multipart/form-data with the file in "file".
+func (h *imageHandler) UploadImage(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.cfg.MaxUploadSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondImageError(c, model.ErrImageTooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "File is required"})
+		return
+	}
+
+	if fileHeader.Size > h.cfg.MaxUploadSize {
+		respondImageError(c, model.ErrImageTooLarge)
+		return
+	}
+
+	var req dto.UploadImageRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.cfg.MaxUploadSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Failed to read file"})
+		return
+	}
+
+	input := service.UploadImageInput{
+		UserID:  userID,
+		Role:    middleware.GetUserRole(c),
+		Data:    data,
+		AltText: req.AltText,
+		Caption: req.Caption,
+	}
+	if req.ArticleID != "" {
+		articleID := uuid.MustParse(req.ArticleID)
+		input.ArticleID = &articleID
+	}
+
+	image, existing, err := h.imageService.UploadImage(input)
+	if err != nil {
+		respondImageError(c, err)
+		return
+	}
+
+	if existing {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Image was already uploaded",
+			"data":    gin.H{"image": toImageResponse(image)},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Image uploaded successfully",
+		"data":    gin.H{"image": toImageResponse(image)},
+	})
+}
+
+// **Upload Image From URL**
+func (h *imageHandler) UploadImageFromURL(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	var req dto.RemoteImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	input := service.UploadImageInput{
+		UserID:    userID,
+		Role:      middleware.GetUserRole(c),
+		ArticleID: req.ArticleID,
+		AltText:   req.AltText,
+		Caption:   req.Caption,
+	}
+
+	image, existing, err := h.imageService.UploadImageFromURL(input, req.URL)
+	if err != nil {
+		respondImageError(c, err)
+		return
+	}
+
+	if existing {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Image was already uploaded",
+			"data":    gin.H{"image": toImageResponse(image)},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Image uploaded successfully",
+		"data":    gin.H{"image": toImageResponse(image)},
+	})
+}
+
+// **Get Image By ID**
+func (h *imageHandler) GetImageByID(c *gin.Context) {
+	imageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid image ID"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	image, err := h.imageService.GetImageByID(imageID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"image": toImageResponse(image)},
+	})
+}
+
+// **Update Image**
+func (h *imageHandler) UpdateImage(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	imageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid image ID"})
+		return
+	}
+
+	var req dto.UpdateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	image, err := h.imageService.UpdateImage(imageID, userID, middleware.GetUserRole(c), req)
+	if err != nil {
+		respondImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Image updated successfully",
+		"data":    gin.H{"image": toImageResponse(image)},
+	})
+}
+
+// **Delete Image**
+func (h *imageHandler) DeleteImage(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	imageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid image ID"})
+		return
+	}
+
+	if err := h.imageService.DeleteImage(imageID, userID, middleware.GetUserRole(c)); err != nil {
+		respondImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Image deleted successfully",
+	})
+}
+
+// **Get My Images (Gallery)**
+func (h *imageHandler) GetMyImages(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	page, limit := parsePageQuery(c, 20)
+
+	images, total, err := h.imageService.ListUserImages(userID, page, limit)
+	if err != nil {
+		respondImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    toImagePageResponse(images, page, limit, total),
+	})
+}
+
+// **Get Article Images (Gallery)**
+func (h *imageHandler) GetArticleImages(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	page, limit := parsePageQuery(c, 20)
+
+	images, total, err := h.imageService.ListArticleImages(articleID, userID, middleware.GetUserRole(c), page, limit)
+	if err != nil {
+		respondImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    toImagePageResponse(images, page, limit, total),
+	})
+}
+
+func respondImageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrImageNotFound), errors.Is(err, model.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrImageForbidden), errors.Is(err, model.ErrArticleForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrImageTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrUnsupportedImageType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrInvalidImageURL):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrRemoteImageFailed):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "errors": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "errors": err.Error()})
+	}
+}
+
+func toImagePageResponse(images []model.Image, page, limit int, total int64) dto.PageResponse[dto.ImageResponse] {
+	responses := make([]dto.ImageResponse, 0, len(images))
+	for i := range images {
+		responses = append(responses, toImageResponse(&images[i]))
+	}
+
+	return dto.NewOffsetPage(responses, page, limit, total)
+}
+
+func toImageResponse(image *model.Image) dto.ImageResponse {
+	variants := make([]dto.ImageVariantResponse, 0, len(image.Variants))
+	srcset := make([]string, 0, len(image.Variants)+1)
+	for _, variant := range image.Variants {
+		variants = append(variants, dto.ImageVariantResponse{
+			URL:    variant.URL,
+			Width:  variant.Width,
+			Height: variant.Height,
+			Size:   variant.Size,
+		})
+		srcset = append(srcset, fmt.Sprintf("%s %dw", variant.URL, variant.Width))
+	}
+	if image.Width > 0 {
+		srcset = append(srcset, fmt.Sprintf("%s %dw", image.URL, image.Width))
+	}
+
+	return dto.ImageResponse{
+		ID:         image.ID,
+		URL:        image.URL,
+		MimeType:   image.MimeType,
+		Size:       image.Size,
+		Width:      image.Width,
+		Height:     image.Height,
+		AltText:    image.AltText,
+		Caption:    image.Caption,
+		ArticleID:  image.ArticleID,
+		UploadedBy: image.UploadedBy,
+		Variants:   variants,
+		Srcset:     strings.Join(srcset, ", "),
+		CreatedAt:  image.CreatedAt,
+	}
+}
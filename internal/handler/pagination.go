@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+// maxPageLimit caps the page size of every list endpoint.
+const maxPageLimit = 100
+
+// parsePageQuery reads the page and limit of an offset paginated list,
+// falling back to the first page and defaultLimit.
+func parsePageQuery(c *gin.Context, defaultLimit int) (int, int) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	return page, parseLimitQuery(c, defaultLimit)
+}
+
+func parseLimitQuery(c *gin.Context, defaultLimit int) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		return defaultLimit
+	}
+	return limit
+}
+
+// bindArticleListRequest reads the filters, sort and cursor of an article
+// listing. It answers 400 itself and returns false when they are invalid.
+func bindArticleListRequest(c *gin.Context) (dto.ArticleListRequest, bool) {
+	req := dto.ArticleListRequest{
+		Status:     c.Query("status"),
+		AuthorID:   c.Query("author_id"),
+		CategoryID: c.Query("category_id"),
+		TagID:      c.Query("tag_id"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+		Sort:       c.Query("sort"),
+		Order:      c.Query("order"),
+		Cursor:     c.Query("cursor"),
+		Limit:      parseLimitQuery(c, 10),
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return req, false
+	}
+	return req, true
+}
+
+func respondArticleListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrInvalidCursor),
+		errors.Is(err, model.ErrDateFilterInvalid),
+		errors.Is(err, model.ErrDateFilterRange):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrArticleListForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "errors": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "errors": err.Error()})
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type SearchHandler interface {
+	SearchArticles(c *gin.Context)
+}
+
+type searchHandler struct {
+	searchService service.SearchService
+}
+
+func NewSearchHandler(searchService service.SearchService) SearchHandler {
+	return &searchHandler{
+		searchService: searchService,
+	}
+}
+
+// **Search Articles**
+func (h *searchHandler) SearchArticles(c *gin.Context) {
+	page, limit := parsePageQuery(c, 10)
+
+	req := dto.ArticleSearchRequest{
+		Query:      c.Query("q"),
+		CategoryID: c.Query("category_id"),
+		TagID:      c.Query("tag_id"),
+		AuthorID:   c.Query("author_id"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+		Page:       page,
+		Limit:      limit,
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	result, err := h.searchService.SearchArticles(req)
+	if err != nil {
+		respondArticleListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
@@ -1 +1,223 @@
 package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type SEOHandler interface {
+	GetSEOMetadata(c *gin.Context)
+	UpdateSEOMetadata(c *gin.Context)
+	DeleteSEOMetadata(c *gin.Context)
+	PreviewSearchResult(c *gin.Context)
+	ScoreArticle(c *gin.Context)
+	GetSocialMetadata(c *gin.Context)
+	GetHeadFragment(c *gin.Context)
+}
+
+type seoHandler struct {
+	seoService service.SEOService
+}
+
+func NewSEOHandler(seoService service.SEOService) SEOHandler {
+	return &seoHandler{
+		seoService: seoService,
+	}
+}
+
+// **Get SEO Metadata**
+func (h *seoHandler) GetSEOMetadata(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	metadata, err := h.seoService.GetSEOMetadata(articleID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondSEOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"seo": metadata},
+	})
+}
+
+// **Update SEO Metadata**
+func (h *seoHandler) UpdateSEOMetadata(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	var req dto.SEOMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	metadata, err := h.seoService.UpdateSEOMetadata(articleID, userID, middleware.GetUserRole(c), req)
+	if err != nil {
+		respondSEOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SEO metadata updated successfully",
+		"data":    gin.H{"seo": metadata},
+	})
+}
+
+// **Delete SEO Metadata**
+func (h *seoHandler) DeleteSEOMetadata(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	if err := h.seoService.DeleteSEOMetadata(articleID, userID, middleware.GetUserRole(c)); err != nil {
+		respondSEOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SEO metadata reset to generated defaults",
+	})
+}
+
+// **Preview Search Result**
+func (h *seoHandler) PreviewSearchResult(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	preview, err := h.seoService.PreviewSearchResult(articleID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondSEOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"preview": preview},
+	})
+}
+
+// **Score Article SEO**
+// Optional ?keyword= overrides the focus keyword.
+func (h *seoHandler) ScoreArticle(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	score, err := h.seoService.ScoreArticle(articleID, userID, middleware.GetUserRole(c), c.Query("keyword"))
+	if err != nil {
+		respondSEOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"score": score},
+	})
+}
+
+// **Get Social Metadata**
+func (h *seoHandler) GetSocialMetadata(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	social, err := h.seoService.GetSocialMetadata(articleID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondSEOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"social": social},
+	})
+}
+
+// **Get Head Fragment**
+// The same tags as HTML, for server-side rendering into <head>.
+func (h *seoHandler) GetHeadFragment(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	social, err := h.seoService.GetSocialMetadata(articleID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondSEOError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(social.Head))
+}
+
+func respondSEOError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrArticleForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "errors": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "errors": err.Error()})
+	}
+}
@@ -1 +1,344 @@
 package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/service"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type TagHandler interface {
+	GetTags(c *gin.Context)
+	AutocompleteTags(c *gin.Context)
+	GetTagByID(c *gin.Context)
+	GetTagBySlug(c *gin.Context)
+	CreateTag(c *gin.Context)
+	UpdateTag(c *gin.Context)
+	DeleteTag(c *gin.Context)
+	MergeTags(c *gin.Context)
+
+	GetArticleTags(c *gin.Context)
+	SetArticleTags(c *gin.Context)
+	SuggestArticleTags(c *gin.Context)
+}
+
+type tagHandler struct {
+	tagService service.TagService
+}
+
+func NewTagHandler(tagService service.TagService) TagHandler {
+	return &tagHandler{
+		tagService: tagService,
+	}
+}
+
+// **Get All Tags**
+func (h *tagHandler) GetTags(c *gin.Context) {
+	tags, err := h.tagService.ListTags()
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"tags": tags},
+	})
+}
+
+// **Autocomplete Tags**
+func (h *tagHandler) AutocompleteTags(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	tags, err := h.tagService.AutocompleteTags(c.Query("q"), limit)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"tags": tags},
+	})
+}
+
+// **Get Tag By ID**
+func (h *tagHandler) GetTagByID(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid tag ID"})
+		return
+	}
+
+	tag, err := h.tagService.GetTagByID(tagID)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"tag": tag},
+	})
+}
+
+// **Get Tag By Slug**
+func (h *tagHandler) GetTagBySlug(c *gin.Context) {
+	tag, err := h.tagService.GetTagBySlug(c.Param("slug"))
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"tag": tag},
+	})
+}
+
+// **Create Tag**
+func (h *tagHandler) CreateTag(c *gin.Context) {
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	tag, nearMatches, err := h.tagService.CreateTag(req)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Tag created successfully",
+		"data":    gin.H{"tag": tag, "near_matches": nearMatches},
+	})
+}
+
+// **Update Tag**
+func (h *tagHandler) UpdateTag(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid tag ID"})
+		return
+	}
+
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(tagID, req)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tag updated successfully",
+		"data":    gin.H{"tag": tag},
+	})
+}
+
+// **Delete Tag**
+func (h *tagHandler) DeleteTag(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid tag ID"})
+		return
+	}
+
+	if err := h.tagService.DeleteTag(tagID); err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tag deleted successfully",
+	})
+}
+
+// **Merge Tags**
+// The tag in the URL survives; the tags in source_ids are merged into it.
+func (h *tagHandler) MergeTags(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid tag ID"})
+		return
+	}
+
+	var req dto.MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	tag, err := h.tagService.MergeTags(tagID, req.SourceIDs)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tags merged successfully",
+		"data":    gin.H{"tag": tag},
+	})
+}
+
+// **Get Article Tags**
+func (h *tagHandler) GetArticleTags(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+
+	tags, err := h.tagService.GetArticleTags(articleID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"tags": tags},
+	})
+}
+
+// **Set Article Tags**
+func (h *tagHandler) SetArticleTags(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	var req dto.ArticleTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return
+	}
+
+	tags, nearMatches, err := h.tagService.SetArticleTags(articleID, userID, middleware.GetUserRole(c), req)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Article tags updated successfully",
+		"data":    gin.H{"tags": tags, "near_matches": nearMatches},
+	})
+}
+
+// **Suggest Article Tags**
+func (h *tagHandler) SuggestArticleTags(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": "Invalid article ID"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+
+	suggestions, err := h.tagService.SuggestArticleTags(articleID, userID, middleware.GetUserRole(c), limit)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"suggestions": suggestions},
+	})
+}
+
+func respondTagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrTagNotFound),
+		errors.Is(err, model.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrArticleForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrTagNameExists),
+		errors.Is(err, model.ErrTagSlugExists):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrTagMergeItself),
+		errors.Is(err, model.ErrTagNameInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "errors": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "errors": err.Error()})
+	}
+}
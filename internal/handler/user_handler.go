@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"log"
 	"net/http"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/tsaqiffatih/minddrift-server/config"
 	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/middleware"
 	"github.com/tsaqiffatih/minddrift-server/internal/model"
 	"github.com/tsaqiffatih/minddrift-server/internal/service"
 	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
@@ -16,12 +18,14 @@ import (
 type UserHandler interface {
 	RegisterUser(c *gin.Context)
 	LoginUser(c *gin.Context)
+	LoginTwoFA(c *gin.Context)
 	VerifyEmail(c *gin.Context)
 	ResendEmail(c *gin.Context)
 	ResetPassword(c *gin.Context)        //Change The Password
 	ValidateResetToken(c *gin.Context)   //Validate Token Reset Password
 	RequestResetPassword(c *gin.Context) //Request Reset Password and send email
 
+	SetupTwoFA(c *gin.Context)
 	EnableTwoFA(c *gin.Context)
 	DisableTwoFA(c *gin.Context)
 	UpdateUserProfile(c *gin.Context)
@@ -119,7 +123,7 @@ func (h *userHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	token, err := h.userService.LoginUser(loginData.Email, loginData.Password)
+	session, err := h.userService.LoginUser(loginData.Email, loginData.Password)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"success": false,
@@ -128,12 +132,10 @@ func (h *userHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	log.Println("Token:", token)
-
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Login success",
-		"data":    gin.H{"token": token},
+		"data":    session,
 	})
 }
 
@@ -310,44 +312,148 @@ func (h *userHandler) RequestResetPassword(c *gin.Context) {
 	})
 }
 
-// **Activate 2FA**
-func (h *userHandler) EnableTwoFA(c *gin.Context) {
-	var request struct {
-		Secret string `json:"secret"`
+// **Login Second Step (2FA)**
+func (h *userHandler) LoginTwoFA(c *gin.Context) {
+	var req dto.TwoFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return
 	}
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": err.Error()})
+
+	if err := utils.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
 		return
 	}
 
-	userID, err := uuid.Parse(c.Param("id"))
+	session, err := h.userService.LoginTwoFA(req.ChallengeToken, req.Code)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": "ID tidak valid"})
+		status := http.StatusUnauthorized
+		if errors.Is(err, model.ErrTwoFALocked) {
+			status = http.StatusTooManyRequests
+		}
+
+		c.JSON(status, gin.H{
+			"success": false,
+			"errors":  err.Error(),
+		})
 		return
 	}
 
-	if err := h.userService.EnableTwoFA(userID, request.Secret); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Login success",
+		"data":    session,
+	})
+}
+
+// **Setup 2FA**
+func (h *userHandler) SetupTwoFA(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	setup, err := h.userService.SetupTwoFA(userID)
+	if err != nil {
+		respondTwoFAError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "2FA berhasil diaktifkan"})
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Scan the QR code and confirm with a code from your authenticator app",
+		"data":    setup,
+	})
+}
+
+// **Activate 2FA**
+func (h *userHandler) EnableTwoFA(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
+		return
+	}
+
+	var req dto.TwoFACodeRequest
+	if !bindTwoFACode(c, &req) {
+		return
+	}
+
+	recoveryCodes, err := h.userService.EnableTwoFA(userID, req.Code)
+	if err != nil {
+		respondTwoFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "2FA berhasil diaktifkan",
+		"data":    gin.H{"recovery_codes": recoveryCodes},
+	})
 }
 
 // **Unactivate 2FA**
 func (h *userHandler) DisableTwoFA(c *gin.Context) {
-	userID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": "ID tidak valid"})
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": "Unauthorized"})
 		return
 	}
 
-	if err := h.userService.DisableTwoFA(userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+	var req dto.TwoFACodeRequest
+	if !bindTwoFACode(c, &req) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "2FA berhasil dinonaktifkan"})
+	if err := h.userService.DisableTwoFA(userID, req.Code); err != nil {
+		respondTwoFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "2FA berhasil dinonaktifkan",
+	})
+}
+
+func bindTwoFACode(c *gin.Context, req *dto.TwoFACodeRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatBindingError(err),
+		})
+		return false
+	}
+
+	if err := utils.ValidateStruct(*req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  utils.FormatValidationError(err),
+		})
+		return false
+	}
+
+	return true
+}
+
+func respondTwoFAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrInvalidTwoFACode):
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrTwoFANotSetUp), errors.Is(err, model.ErrTwoFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "errors": err.Error()})
+	case errors.Is(err, model.ErrTwoFALocked):
+		c.JSON(http.StatusTooManyRequests, gin.H{"success": false, "errors": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "errors": err.Error()})
+	}
 }
 
 // **Update Profil**
@@ -370,9 +476,7 @@ func (h *userHandler) UpdateUserProfile(c *gin.Context) {
 // **Change User Role**
 func (h *userHandler) ChangeUserRole(c *gin.Context) {
 	var roleData struct {
-		AdminID string         `json:"admin_id"`
-		UserID  string         `json:"user_id"`
-		NewRole model.UserRole `json:"new_role"`
+		NewRole string `json:"new_role" validate:"required,oneof=admin editor penulis"`
 	}
 
 	if err := c.ShouldBindJSON(&roleData); err != nil {
@@ -380,20 +484,19 @@ func (h *userHandler) ChangeUserRole(c *gin.Context) {
 		return
 	}
 
-	adminID, err := uuid.Parse(roleData.AdminID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": "ID admin tidak valid"})
+	if err := utils.ValidateStruct(roleData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": utils.FormatValidationError(err)})
 		return
 	}
 
-	userID, err := uuid.Parse(roleData.UserID)
+	userID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"errors": "ID pengguna tidak valid"})
 		return
 	}
 
-	if err := h.userService.ChangeUserRole(adminID, userID, roleData.NewRole); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"errors": err.Error()})
+	if err := h.userService.ChangeUserRole(userID, model.UserRole(roleData.NewRole)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"errors": err.Error()})
 		return
 	}
 
@@ -5,7 +5,9 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
 	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
 )
 
@@ -50,3 +52,41 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OptionalAuthMiddleware sets userID and role when a valid token is present,
+// but lets anonymous requests through (used by public read endpoints).
+func OptionalAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenParts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(tokenParts) == 2 && tokenParts[0] == "Bearer" {
+			if claims, err := utils.VerifyJWT(cfg, tokenParts[1]); err == nil {
+				c.Set("userID", claims.UserID)
+				c.Set("role", claims.Role)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// GetUserID returns the authenticated user ID set by AuthMiddleware.
+func GetUserID(c *gin.Context) (uuid.UUID, bool) {
+	value, exists := c.Get("userID")
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	userID, ok := value.(uuid.UUID)
+	return userID, ok
+}
+
+// GetUserRole returns the authenticated user role set by AuthMiddleware.
+func GetUserRole(c *gin.Context) model.UserRole {
+	value, exists := c.Get("role")
+	if !exists {
+		return ""
+	}
+
+	role, _ := value.(model.UserRole)
+	return role
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+)
+
+// RequirePermission only lets users whose role grants permission through.
+// It must be registered after AuthMiddleware.
+func RequirePermission(permission constant.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !constant.HasPermission(GetUserRole(c), permission) {
+			forbidden(c)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func forbidden(c *gin.Context) {
+	c.JSON(http.StatusForbidden, gin.H{
+		"success": false,
+		"error":   "You do not have permission to perform this action",
+	})
+	c.Abort()
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidActionToken = errors.New("Invalid token or token has expired")
+
+type TokenPurpose string
+
+const (
+	PurposeEmailVerification TokenPurpose = "email_verification"
+	PurposePasswordReset     TokenPurpose = "password_reset"
+	PurposeTwoFALogin        TokenPurpose = "two_fa_login"
+)
+
+// ActionToken is a single-use token sent by email. A token is only valid for
+// the purpose it was issued for, and only its SHA-256 hash is stored.
+type ActionToken struct {
+	ID        uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    uuid.UUID    `gorm:"type:uuid;not null;index"`
+	Purpose   TokenPurpose `gorm:"type:varchar(32);not null"`
+	TokenHash string       `gorm:"type:char(64);uniqueIndex;not null"`
+	ExpiresAt time.Time    `gorm:"not null"`
+	UsedAt    *time.Time   `gorm:"default:null"`
+	CreatedAt time.Time    `gorm:"autoCreateTime"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+}
@@ -1,16 +1,121 @@
 package model
 
 import (
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrAnalyticsRangeTooLong = errors.New("the range is too long for the interval")
+	ErrAnalyticsForbidden    = errors.New("you are not allowed to view these analytics")
+	ErrAnalyticsFromRequired = errors.New("a custom range needs a start date")
+	ErrAnalyticsRateLimited  = errors.New("too many analytics hits, slow down")
+)
+
+type AnalyticsEventType string
+
+const (
+	AnalyticsView      AnalyticsEventType = "view"
+	AnalyticsHeartbeat AnalyticsEventType = "heartbeat"
+)
+
+// TrafficSourceType is the channel a view came through.
+type TrafficSourceType string
+
+const (
+	TrafficDirect   TrafficSourceType = "direct"
+	TrafficSearch   TrafficSourceType = "search"
+	TrafficSocial   TrafficSourceType = "social"
+	TrafficEmail    TrafficSourceType = "email"
+	TrafficInternal TrafficSourceType = "internal"
+	TrafficReferral TrafficSourceType = "referral"
+)
+
+type AnalyticsGranularity string
+
+const (
+	AnalyticsHourly AnalyticsGranularity = "hour"
+	AnalyticsDaily  AnalyticsGranularity = "day"
+)
+
+// Analytic holds the running totals of one article. AverageTimeSpent is
+// TotalTimeSpent divided by Views, in seconds.
 type Analytic struct {
 	gorm.Model
-	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	ArticleID       uuid.UUID `gorm:"type:uuid;not null"`
-	Views           int       `gorm:"default:0"`
-	UniqueVisitors  int       `gorm:"default:0"`
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ArticleID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	Views            int       `gorm:"default:0"`
+	UniqueVisitors   int       `gorm:"default:0"`
+	TotalTimeSpent   int64     `gorm:"default:0"`
 	AverageTimeSpent int       `gorm:"default:0"`
-	Article         Article   `gorm:"foreignKey:ArticleID"`
+	Article          Article   `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE;"`
+}
+
+// AnalyticsEvent is one raw hit from the beacon: a page view, or a
+// heartbeat carrying the seconds the reader spent on the page since the
+// previous hit. Visitors are only known by a hash that changes every day.
+type AnalyticsEvent struct {
+	ID             uuid.UUID          `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ArticleID      uuid.UUID          `gorm:"type:uuid;not null;index:idx_analytics_events_article_created,priority:1"`
+	Type           AnalyticsEventType `gorm:"type:varchar(10);not null"`
+	VisitorHash    string             `gorm:"type:varchar(32);not null"`
+	Seconds        int                `gorm:"default:0"`
+	Referrer       string             `gorm:"type:varchar(2048)"`
+	ReferrerDomain string             `gorm:"type:varchar(255)"`
+	SourceType     TrafficSourceType  `gorm:"type:varchar(10)"`
+	SourceName     string             `gorm:"type:varchar(255)"`
+	UTMSource      string             `gorm:"type:varchar(255)"`
+	UTMMedium      string             `gorm:"type:varchar(255)"`
+	UTMCampaign    string             `gorm:"type:varchar(255)"`
+	CreatedAt      time.Time          `gorm:"not null;index:idx_analytics_events_article_created,priority:2;index:idx_analytics_events_created"`
+	Article        Article            `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE;"`
+}
+
+// AnalyticsRollup holds the totals of one article over one hour or one UTC
+// day, computed from the raw events. UniqueVisitors counts distinct
+// visitors within the bucket only.
+type AnalyticsRollup struct {
+	ArticleID      uuid.UUID            `gorm:"type:uuid;primaryKey"`
+	Granularity    AnalyticsGranularity `gorm:"type:varchar(4);primaryKey;index:idx_analytics_rollups_bucket,priority:1"`
+	BucketStart    time.Time            `gorm:"primaryKey;index:idx_analytics_rollups_bucket,priority:2"`
+	Views          int64                `gorm:"not null;default:0"`
+	UniqueVisitors int64                `gorm:"not null;default:0"`
+	TotalTimeSpent int64                `gorm:"not null;default:0"`
+	Article        Article              `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE;"`
+}
+
+// AnalyticsSourceRollup counts the views of one article on one UTC day by
+// where they came from. An empty ReferrerDomain means no referrer was sent.
+// SourceType and SourceName are the classification of the latest of the
+// views, which only differs from the others when the rules changed.
+type AnalyticsSourceRollup struct {
+	ArticleID      uuid.UUID         `gorm:"type:uuid;primaryKey"`
+	Day            time.Time         `gorm:"type:date;primaryKey"`
+	ReferrerDomain string            `gorm:"type:varchar(255);primaryKey"`
+	UTMSource      string            `gorm:"type:varchar(255);primaryKey"`
+	UTMMedium      string            `gorm:"type:varchar(255);primaryKey"`
+	UTMCampaign    string            `gorm:"type:varchar(255);primaryKey"`
+	SourceType     TrafficSourceType `gorm:"type:varchar(10);not null;default:''"`
+	SourceName     string            `gorm:"type:varchar(255);not null;default:''"`
+	Views          int64             `gorm:"not null;default:0"`
+	Article        Article           `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE;"`
+}
+
+// AnalyticsVisitor records that a visitor viewed an article on a day, so
+// each visitor is counted once per article per day.
+type AnalyticsVisitor struct {
+	ArticleID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Day         time.Time `gorm:"type:date;primaryKey"`
+	VisitorHash string    `gorm:"type:varchar(32);primaryKey"`
+}
+
+// AnalyticsSalt is the secret mixed into visitor hashes for one day. Salts
+// of past days are deleted, after which their hashes cannot be linked to
+// an IP address or to each other.
+type AnalyticsSalt struct {
+	Day  time.Time `gorm:"type:date;primaryKey"`
+	Salt []byte    `gorm:"type:bytea;not null"`
 }
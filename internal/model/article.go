@@ -1,27 +1,43 @@
 package model
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrArticleNotFound   = errors.New("article not found")
+	ErrArticleForbidden  = errors.New("you are not allowed to modify this article")
+	ErrSlugAlreadyExists = errors.New("slug already exists")
+
+	ErrArticleVersionNotFound = errors.New("article version not found")
+
+	ErrDateFilterInvalid    = errors.New("dates must be formatted as YYYY-MM-DD or RFC 3339")
+	ErrDateFilterRange      = errors.New("the start date must not be after the end date")
+	ErrInvalidCursor        = errors.New("invalid pagination cursor")
+	ErrArticleListForbidden = errors.New("you are not allowed to list unpublished articles")
+)
+
 type ArticleStatus string
 
 const (
 	Draft     ArticleStatus = "draft"
 	Review    ArticleStatus = "review"
 	Published ArticleStatus = "published"
+	Scheduled ArticleStatus = "scheduled"
 )
 
 type Article struct {
-	ID          uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ID          uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey;index:idx_articles_published_at_id,priority:2"`
 	Title       string        `gorm:"not null"`
 	Content     string        `gorm:"type:text;not null"`
 	Slug        string        `gorm:"unique;not null"`
 	Status      ArticleStatus `gorm:"type:varchar(10);default:'draft'"`
 	AuthorID    uuid.UUID     `gorm:"type:uuid;not null"`
-	PublishedAt *time.Time    `gorm:"default:null"`
+	PublishedAt *time.Time    `gorm:"default:null;index:idx_articles_published_at_id,priority:1"`
+	ScheduledAt *time.Time    `gorm:"default:null;index"`
 	CreatedAt   time.Time     `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time     `gorm:"autoUpdateTime"`
 
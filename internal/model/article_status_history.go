@@ -0,0 +1,29 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidStatusTransition = errors.New("invalid article status transition")
+	ErrTransitionNotAllowed    = errors.New("you are not allowed to perform this status transition")
+	ErrTransitionReasonMissing = errors.New("a reason is required for this status transition")
+	ErrInvalidScheduleTime     = errors.New("scheduled_at must be a time in the future")
+	ErrStatusChanged           = errors.New("the article status has changed, reload it and try again")
+)
+
+type ArticleStatusHistory struct {
+	ID         uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ArticleID  uuid.UUID     `gorm:"type:uuid;not null;index"`
+	FromStatus ArticleStatus `gorm:"type:varchar(10);not null"`
+	ToStatus   ArticleStatus `gorm:"type:varchar(10);not null"`
+	ActorID    uuid.UUID     `gorm:"type:uuid;not null"`
+	Reason     string        `gorm:"type:text"`
+	CreatedAt  time.Time     `gorm:"autoCreateTime"`
+
+	Article Article `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE;"`
+	Actor   User    `gorm:"foreignKey:ActorID"`
+}
@@ -8,10 +8,11 @@ import (
 
 type ArticleVersion struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	ArticleID uuid.UUID `gorm:"type:uuid;not null"`
+	ArticleID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_article_versions_article_version,priority:1"`
+	Version   int       `gorm:"not null;default:1;uniqueIndex:idx_article_versions_article_version,priority:2"`
 	Title     string    `gorm:"not null"`
 	Content   string    `gorm:"type:text;not null"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 
-	Article Article `gorm:"foreignKey:ArticleID"`
+	Article Article `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE;"`
 }
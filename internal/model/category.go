@@ -1,14 +1,28 @@
 package model
 
 import (
+	"errors"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategoryNameExists    = errors.New("category name already exists")
+	ErrCategorySlugExists    = errors.New("category slug already exists")
+	ErrCategoryCycle         = errors.New("a category cannot be moved below itself or its descendants")
+	ErrParentCategoryMissing = errors.New("parent category not found")
+)
+
+// Category forms a tree through ParentID; top-level categories have none.
 type Category struct {
 	gorm.Model
-	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Name        string    `gorm:"unique;not null"`
-	Description string    `gorm:"type:text"`
-	Articles    []Article `gorm:"many2many:article_categories;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name        string     `gorm:"unique;not null"`
+	Slug        string     `gorm:"type:varchar(120);uniqueIndex"`
+	Description string     `gorm:"type:text"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index"`
+	Parent      *Category  `gorm:"foreignKey:ParentID;constraint:OnDelete:SET NULL;"`
+	Articles    []Article  `gorm:"many2many:article_categories;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
@@ -1,16 +1,53 @@
 package model
 
 import (
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrCommentForbidden = errors.New("you are not allowed to manage this comment")
+	ErrInvalidParent    = errors.New("parent comment does not belong to this article")
+	ErrGuestInfoMissing = errors.New("name and email are required to comment as a guest")
+)
+
+type CommentStatus string
+
+const (
+	CommentPending  CommentStatus = "pending"
+	CommentApproved CommentStatus = "approved"
+	CommentSpam     CommentStatus = "spam"
+	CommentRejected CommentStatus = "rejected"
+)
+
 type Comment struct {
 	gorm.Model
-	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	ArticleID uuid.UUID `gorm:"type:uuid;not null"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null"`
-	Content   string    `gorm:"type:text;not null"`
-	Article   Article   `gorm:"foreignKey:ArticleID"`
-	User      User      `gorm:"foreignKey:UserID"`
+	ID            uuid.UUID     `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ArticleID     uuid.UUID     `gorm:"type:uuid;not null"`
+	UserID        *uuid.UUID    `gorm:"type:uuid"` // nil for guest comments
+	GuestName     string        `gorm:"type:varchar(100)"`
+	GuestEmail    string        `gorm:"type:varchar(255)"`
+	ParentID      *uuid.UUID    `gorm:"type:uuid;index"`
+	RootID        *uuid.UUID    `gorm:"type:uuid;index"` // top-level comment of the thread, nil for top-level comments
+	Content       string        `gorm:"type:text;not null"`
+	Status        CommentStatus `gorm:"type:varchar(10);default:'pending';index"`
+	IsAuthorReply bool          `gorm:"default:false"`
+
+	IPAddress        string     `gorm:"type:varchar(45)"`
+	SpamScore        float64    `gorm:"default:0"`
+	SpamReasons      string     `gorm:"type:text"`
+	TrainedLabel     string     `gorm:"type:varchar(4)"` // label the spam classifier learned from this comment
+	ConfirmationHash string     `gorm:"type:char(64);index"`
+	EmailConfirmedAt *time.Time `gorm:"default:null"`
+
+	Article Article `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE;"`
+	User    User    `gorm:"foreignKey:UserID"`
+}
+
+func (c *Comment) IsGuest() bool {
+	return c.UserID == nil
 }
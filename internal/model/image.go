@@ -1,18 +1,53 @@
 package model
 
 import (
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrImageNotFound        = errors.New("image not found")
+	ErrImageForbidden       = errors.New("you are not allowed to manage this image")
+	ErrImageTooLarge        = errors.New("image exceeds the maximum upload size")
+	ErrUnsupportedImageType = errors.New("unsupported image type, allowed types are JPEG, PNG, GIF and WebP")
+	ErrInvalidImageURL      = errors.New("image URL must be a public http or https address")
+	ErrRemoteImageFailed    = errors.New("failed to download image from URL")
+)
+
+// Image is an uploaded file. Identical uploads share one stored object,
+// addressed by ContentHash, so StorageKey may appear on several rows.
 type Image struct {
 	gorm.Model
-	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	URL         string    `gorm:"not null"`
+	StorageKey  string    `gorm:"type:varchar(255);not null"`
+	ContentHash string    `gorm:"type:char(64);not null;index"`
+	MimeType    string    `gorm:"type:varchar(50);not null"`
+	Size        int64     `gorm:"not null"`
+	Width       int
+	Height      int
+	AltText     string
+	Caption     string
+	ArticleID   *uuid.UUID `gorm:"type:uuid;index"` // nil for images only in the uploader's gallery
+	Article     Article    `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE;"`
+	UploadedBy  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	User        User       `gorm:"foreignKey:UploadedBy"`
+
+	Variants []ImageVariant `gorm:"foreignKey:ImageID;constraint:OnDelete:CASCADE;"`
+}
+
+// ImageVariant is a downscaled copy of an image used to build its srcset.
+type ImageVariant struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ImageID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Width      int       `gorm:"not null"`
+	Height     int       `gorm:"not null"`
+	MimeType   string    `gorm:"type:varchar(50);not null"`
+	Size       int64     `gorm:"not null"`
+	StorageKey string    `gorm:"type:varchar(255);not null"`
 	URL        string    `gorm:"not null"`
-	AltText    string
-	Caption    string
-	ArticleID  uuid.UUID `gorm:"type:uuid;not null"`
-	Article    Article   `gorm:"foreignKey:ArticleID"`
-	UploadedBy uuid.UUID `gorm:"type:uuid;not null"`
-	User       User      `gorm:"foreignKey:UploadedBy"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
 }
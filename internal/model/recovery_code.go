@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode is a one-time 2FA bypass code. Only its SHA-256 hash is stored.
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	CodeHash  string     `gorm:"type:char(64);not null"`
+	UsedAt    *time.Time `gorm:"default:null"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+}
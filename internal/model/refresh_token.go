@@ -0,0 +1,29 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used, all sessions in this family were revoked")
+)
+
+// RefreshToken is one link in a rotation chain. Every token issued from the
+// same login shares a FamilyID, so reuse of a rotated token can revoke the
+// whole chain.
+type RefreshToken struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;index"`
+	FamilyID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	TokenHash    string     `gorm:"type:char(64);uniqueIndex;not null"`
+	ExpiresAt    time.Time  `gorm:"not null"`
+	RevokedAt    *time.Time `gorm:"default:null"`
+	ReplacedByID *uuid.UUID `gorm:"type:uuid;default:null"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+}
@@ -1,16 +1,23 @@
 package model
 
 import (
+	"errors"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrSitemapPageNotFound = errors.New("sitemap page not found")
+	ErrFeedNotFound        = errors.New("feed not found")
+)
+
 type SEOMetadata struct {
 	gorm.Model
 	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	ArticleID       uuid.UUID `gorm:"type:uuid;not null"`
+	ArticleID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
 	MetaTitle       string
 	MetaDescription string  `gorm:"type:text"`
-	Keywords        string  `gorm:"type:text"`
-	Article         Article `gorm:"foreignKey:ArticleID"`
+	Keywords        string  `gorm:"type:text"` // comma separated
+	Article         Article `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE;"`
 }
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SlugRedirect maps a slug an article used to have to the article itself,
+// so old links keep resolving after the slug changes.
+type SlugRedirect struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OldSlug   string    `gorm:"uniqueIndex;not null"`
+	ArticleID uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	Article Article `gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE;"`
+}
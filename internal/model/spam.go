@@ -0,0 +1,20 @@
+package model
+
+const (
+	SpamLabel = "spam"
+	HamLabel  = "ham"
+)
+
+// SpamToken holds how often a token appeared in comments moderators marked
+// as spam or approved (ham). It backs the local Bayesian spam classifier.
+type SpamToken struct {
+	Token     string `gorm:"type:varchar(64);primaryKey"`
+	SpamCount int    `gorm:"not null;default:0"`
+	HamCount  int    `gorm:"not null;default:0"`
+}
+
+// SpamCorpus counts the training documents per label.
+type SpamCorpus struct {
+	Label     string `gorm:"type:varchar(4);primaryKey"`
+	Documents int    `gorm:"not null;default:0"`
+}
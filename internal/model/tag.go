@@ -1,13 +1,27 @@
 package model
 
 import (
+	"errors"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrTagNotFound    = errors.New("tag not found")
+	ErrTagNameExists  = errors.New("tag name already exists")
+	ErrTagSlugExists  = errors.New("tag slug already exists")
+	ErrTagMergeItself = errors.New("a tag cannot be merged into itself")
+	ErrTagNameInvalid = errors.New("tag name must contain letters or digits")
+)
+
 type Tag struct {
 	gorm.Model
-	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	Name     string    `gorm:"unique;not null"`
+	ID   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name string    `gorm:"unique;not null"`
+	// NameKey is the normalized name, so spelling variants of a tag
+	// ("Go", "golang") cannot both exist.
+	NameKey  string    `gorm:"type:varchar(120);uniqueIndex;index:idx_tags_name_key_pattern,expression:name_key varchar_pattern_ops"`
+	Slug     string    `gorm:"type:varchar(120);uniqueIndex"`
 	Articles []Article `gorm:"many2many:article_tags;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
@@ -8,7 +8,11 @@ import (
 )
 
 var (
-	ErrEmailAlreadyExists = errors.New("email already exists")
+	ErrEmailAlreadyExists  = errors.New("email already exists")
+	ErrInvalidTwoFACode    = errors.New("invalid two-factor authentication code")
+	ErrTwoFANotSetUp       = errors.New("two-factor authentication has not been set up")
+	ErrTwoFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFALocked         = errors.New("too many invalid two-factor authentication codes, please try again later")
 )
 
 type UserRole string
@@ -28,6 +32,11 @@ type User struct {
 	EmailVerified bool      `gorm:"default:false" json:"email_verified"`
 	TwoFAEnabled  bool      `gorm:"default:false" json:"two_fa_enabled"`
 	TwoFASecret   string    `gorm:"type:text" json:"-"`
-	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	// TwoFALastStep is the last TOTP time step accepted, so a code is only
+	// good once. Failed codes count towards a temporary lock.
+	TwoFALastStep    int64      `gorm:"not null;default:0" json:"-"`
+	TwoFAFailedCount int        `gorm:"not null;default:0" json:"-"`
+	TwoFALockedUntil *time.Time `gorm:"default:null" json:"-"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
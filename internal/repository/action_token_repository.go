@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
+
+type ActionTokenRepository interface {
+	CreateActionToken(token *model.ActionToken) error
+	GetActiveActionToken(hash string, purpose model.TokenPurpose) (*model.ActionToken, error)
+	ConsumeActionToken(id uuid.UUID) (bool, error)
+	InvalidateActionTokens(userID uuid.UUID, purpose model.TokenPurpose) error
+}
+
+type actionTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewActionTokenRepository(db *gorm.DB) ActionTokenRepository {
+	return &actionTokenRepository{
+		db: db,
+	}
+}
+
+func (r *actionTokenRepository) CreateActionToken(token *model.ActionToken) error {
+	return r.db.Omit("User").Create(token).Error
+}
+
+// GetActiveActionToken returns the unused, unexpired token with the given
+// hash and purpose, or nil.
+func (r *actionTokenRepository) GetActiveActionToken(hash string, purpose model.TokenPurpose) (*model.ActionToken, error) {
+	var token model.ActionToken
+	err := r.db.Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", hash, purpose, time.Now()).
+		First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &token, err
+}
+
+// ConsumeActionToken marks the token used. It returns false when the token
+// was already used, so concurrent requests cannot both succeed.
+func (r *actionTokenRepository) ConsumeActionToken(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&model.ActionToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// InvalidateActionTokens marks every outstanding token of the user for
+// purpose as used.
+func (r *actionTokenRepository) InvalidateActionTokens(userID uuid.UUID, purpose model.TokenPurpose) error {
+	return r.db.Model(&model.ActionToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+)
+
+func TestActionTokenRepositoryQueries(t *testing.T) {
+	db, recorder := newDryRunDB(t)
+	repo := NewActionTokenRepository(db)
+	id := uuid.New()
+
+	tests := []struct {
+		name string
+		run  func()
+		want []string
+	}{
+		{
+			name: "only unused, unexpired tokens of the purpose are active",
+			run:  func() { repo.GetActiveActionToken("hash", model.PurposePasswordReset) },
+			want: []string{"token_hash = 'hash'", "purpose = 'password_reset'", "used_at IS NULL", "expires_at > "},
+		},
+		{
+			name: "a token is consumed once",
+			run:  func() { repo.ConsumeActionToken(id) },
+			want: []string{`UPDATE "action_tokens" SET "used_at"=`, "id = '" + id.String() + "'", "AND used_at IS NULL"},
+		},
+		{
+			name: "invalidation is limited to the purpose",
+			run:  func() { repo.InvalidateActionTokens(id, model.PurposeEmailVerification) },
+			want: []string{"user_id = '" + id.String() + "'", "purpose = 'email_verification'", "used_at IS NULL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run()
+			sql := recorder.last(t)
+			for _, part := range tt.want {
+				if !strings.Contains(sql, part) {
+					t.Errorf("SQL %q does not contain %q", sql, part)
+				}
+			}
+		})
+	}
+}
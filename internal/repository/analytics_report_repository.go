@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
+
+// analyticsTopSorts whitelists what top articles can be ordered by.
+var analyticsTopSorts = map[string]string{
+	"views":           "views",
+	"unique_visitors": "unique_visitors",
+	"read_time":       "average_time_spent",
+}
+
+// analyticsSourceColumns whitelists the dimensions traffic can be grouped by.
+var analyticsSourceColumns = map[string]string{
+	"referrer_domain": "s.referrer_domain",
+	"utm_source":      "s.utm_source",
+	"utm_medium":      "s.utm_medium",
+	"utm_campaign":    "s.utm_campaign",
+}
+
+// AnalyticsReportQuery selects rollup buckets starting in [From, To), for
+// all articles or narrowed down to one author or one article.
+type AnalyticsReportQuery struct {
+	Granularity model.AnalyticsGranularity
+	From        time.Time
+	To          time.Time
+	AuthorID    *uuid.UUID
+	ArticleID   *uuid.UUID
+}
+
+// AnalyticsTotals sums rollup buckets. UniqueVisitors is a sum of the
+// per-bucket counts, so a visitor coming back in another bucket counts again.
+type AnalyticsTotals struct {
+	Views          int64
+	UniqueVisitors int64
+	TotalTimeSpent int64
+}
+
+type AnalyticsBucket struct {
+	BucketStart time.Time
+	AnalyticsTotals
+}
+
+type AnalyticsArticleStat struct {
+	ArticleID        uuid.UUID
+	Title            string
+	Slug             string
+	AverageTimeSpent int64
+	AnalyticsTotals
+}
+
+type AnalyticsSourceCount struct {
+	Name  string
+	Views int64
+}
+
+// AnalyticsChannelCount counts views per traffic channel, and per source
+// within the channel when SourceName is grouped on.
+type AnalyticsChannelCount struct {
+	ArticleID  uuid.UUID
+	SourceType model.TrafficSourceType
+	SourceName string
+	Views      int64
+}
+
+type AnalyticsReportRepository interface {
+	Totals(query AnalyticsReportQuery) (AnalyticsTotals, error)
+	Trend(query AnalyticsReportQuery) ([]AnalyticsBucket, error)
+	TopArticles(query AnalyticsReportQuery, sort string, limit int) ([]AnalyticsArticleStat, error)
+	TopSources(query AnalyticsReportQuery, dimension string, limit int) ([]AnalyticsSourceCount, error)
+	Channels(query AnalyticsReportQuery) ([]AnalyticsChannelCount, error)
+	TopChannelSources(query AnalyticsReportQuery, limit int) ([]AnalyticsChannelCount, error)
+	ChannelsByArticle(query AnalyticsReportQuery, articleIDs []uuid.UUID) ([]AnalyticsChannelCount, error)
+}
+
+type analyticsReportRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsReportRepository(db *gorm.DB) AnalyticsReportRepository {
+	return &analyticsReportRepository{
+		db: db,
+	}
+}
+
+const analyticsTotalsColumns = `COALESCE(SUM(r.views), 0) AS views,
+	COALESCE(SUM(r.unique_visitors), 0) AS unique_visitors,
+	COALESCE(SUM(r.total_time_spent), 0) AS total_time_spent`
+
+// sourceRollups selects the daily source counts of the UTC days the range
+// touches.
+func (r *analyticsReportRepository) sourceRollups(query AnalyticsReportQuery) *gorm.DB {
+	from := query.From.UTC().Truncate(24 * time.Hour)
+	to := query.To.UTC().Add(24*time.Hour - time.Nanosecond).Truncate(24 * time.Hour)
+
+	db := r.db.Table("analytics_source_rollups AS s").
+		Joins("JOIN articles a ON a.id = s.article_id").
+		Where("s.day >= ? AND s.day < ?", from, to)
+	return scopeAnalytics(db, query, "s")
+}
+
+// classifiedSourceRollups leaves out views rolled up without a channel.
+func (r *analyticsReportRepository) classifiedSourceRollups(query AnalyticsReportQuery) *gorm.DB {
+	return r.sourceRollups(query).Where("s.source_type <> ''")
+}
+
+func (r *analyticsReportRepository) rollups(query AnalyticsReportQuery) *gorm.DB {
+	db := r.db.Table("analytics_rollups AS r").
+		Joins("JOIN articles a ON a.id = r.article_id").
+		Where("r.granularity = ? AND r.bucket_start >= ? AND r.bucket_start < ?", query.Granularity, query.From, query.To)
+	return scopeAnalytics(db, query, "r")
+}
+
+func scopeAnalytics(db *gorm.DB, query AnalyticsReportQuery, alias string) *gorm.DB {
+	if query.AuthorID != nil {
+		db = db.Where("a.author_id = ?", *query.AuthorID)
+	}
+	if query.ArticleID != nil {
+		db = db.Where(alias+".article_id = ?", *query.ArticleID)
+	}
+	return db
+}
+
+func (r *analyticsReportRepository) Totals(query AnalyticsReportQuery) (AnalyticsTotals, error) {
+	var totals AnalyticsTotals
+	err := r.rollups(query).Select(analyticsTotalsColumns).Scan(&totals).Error
+	return totals, err
+}
+
+// Trend returns the buckets that have data, oldest first.
+func (r *analyticsReportRepository) Trend(query AnalyticsReportQuery) ([]AnalyticsBucket, error) {
+	var buckets []AnalyticsBucket
+	err := r.rollups(query).
+		Select("r.bucket_start, " + analyticsTotalsColumns).
+		Group("r.bucket_start").
+		Order("r.bucket_start").
+		Scan(&buckets).Error
+	return buckets, err
+}
+
+// TopArticles ranks the articles viewed in the range by views, unique
+// visitors or average read time; unknown sorts fall back to views.
+func (r *analyticsReportRepository) TopArticles(query AnalyticsReportQuery, sort string, limit int) ([]AnalyticsArticleStat, error) {
+	column, ok := analyticsTopSorts[sort]
+	if !ok {
+		column = analyticsTopSorts["views"]
+	}
+
+	var stats []AnalyticsArticleStat
+	err := r.rollups(query).
+		Select("r.article_id, a.title, a.slug, " + analyticsTotalsColumns +
+			", COALESCE(SUM(r.total_time_spent), 0) / GREATEST(SUM(r.views), 1) AS average_time_spent").
+		Group("r.article_id, a.title, a.slug").
+		Having("SUM(r.views) > 0").
+		Order(column + " DESC, r.article_id").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// TopSources counts views per referrer domain or UTM parameter over the
+// UTC days the range touches. Views without a value for the dimension are
+// left out, except for referrers, where they are the direct traffic.
+func (r *analyticsReportRepository) TopSources(query AnalyticsReportQuery, dimension string, limit int) ([]AnalyticsSourceCount, error) {
+	column, ok := analyticsSourceColumns[dimension]
+	if !ok {
+		return nil, nil
+	}
+
+	db := r.sourceRollups(query)
+	if dimension != "referrer_domain" {
+		db = db.Where(column + " <> ''")
+	}
+
+	var counts []AnalyticsSourceCount
+	err := db.
+		Select(column + " AS name, SUM(s.views) AS views").
+		Group(column).
+		Order("views DESC, name").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// Channels counts the views of the range per channel, most viewed first.
+func (r *analyticsReportRepository) Channels(query AnalyticsReportQuery) ([]AnalyticsChannelCount, error) {
+	var counts []AnalyticsChannelCount
+	err := r.classifiedSourceRollups(query).
+		Select("s.source_type, SUM(s.views) AS views").
+		Group("s.source_type").
+		Order("views DESC, s.source_type").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// TopChannelSources ranks the search engines, networks, mail providers and
+// referring sites of the range. Direct and internal views have no source.
+func (r *analyticsReportRepository) TopChannelSources(query AnalyticsReportQuery, limit int) ([]AnalyticsChannelCount, error) {
+	var counts []AnalyticsChannelCount
+	err := r.classifiedSourceRollups(query).
+		Where("s.source_name <> ''").
+		Select("s.source_type, s.source_name, SUM(s.views) AS views").
+		Group("s.source_type, s.source_name").
+		Order("views DESC, s.source_type, s.source_name").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// ChannelsByArticle counts the views of the range per channel for each of
+// the given articles.
+func (r *analyticsReportRepository) ChannelsByArticle(query AnalyticsReportQuery, articleIDs []uuid.UUID) ([]AnalyticsChannelCount, error) {
+	if len(articleIDs) == 0 {
+		return nil, nil
+	}
+
+	var counts []AnalyticsChannelCount
+	err := r.classifiedSourceRollups(query).
+		Where("s.article_id IN ?", articleIDs).
+		Select("s.article_id, s.source_type, SUM(s.views) AS views").
+		Group("s.article_id, s.source_type").
+		Order("s.article_id, views DESC, s.source_type").
+		Scan(&counts).Error
+	return counts, err
+}
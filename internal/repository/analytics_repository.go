@@ -1 +1,298 @@
 package repository
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
+
+// eventPurgeChunk bounds how many raw events one delete statement removes,
+// so purging a long backlog does not hold locks on the table for long.
+const eventPurgeChunk = 5000
+
+// visitorInsertChunk keeps multi-row visitor inserts well below the
+// Postgres limit of 65535 bind parameters.
+const visitorInsertChunk = 1000
+
+type AnalyticsRepository interface {
+	EnsureDailySalt(day time.Time, candidate []byte) ([]byte, error)
+	ListPublishedArticleIDs() ([]uuid.UUID, error)
+	RecordEvents(events []model.AnalyticsEvent) error
+	LastRollupBucket() (*time.Time, error)
+	RollupEvents(since time.Time) error
+	PurgeEvents(before time.Time) (int64, error)
+	PurgeHourlyRollups(before time.Time) (int64, error)
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{
+		db: db,
+	}
+}
+
+// EnsureDailySalt returns the salt of the day, storing candidate when the
+// day has none yet, so every server instance hashes with the same salt.
+// Salts and visitor records of earlier days are deleted on the way.
+func (r *analyticsRepository) EnsureDailySalt(day time.Time, candidate []byte) ([]byte, error) {
+	var salt model.AnalyticsSalt
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("INSERT INTO analytics_salts (day, salt) VALUES (?, ?) ON CONFLICT DO NOTHING", day, candidate).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("day = ?", day).First(&salt).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("day < ?", day).Delete(&model.AnalyticsSalt{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("day < ?", day).Delete(&model.AnalyticsVisitor{}).Error
+	})
+	return salt.Salt, err
+}
+
+func (r *analyticsRepository) ListPublishedArticleIDs() ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&model.Article{}).Where("status = ?", model.Published).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// RecordEvents stores a batch of hits and adds them to the running totals
+// of their articles in one transaction. Hits on articles that do not
+// exist or are not published are dropped.
+func (r *analyticsRepository) RecordEvents(events []model.AnalyticsEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		events, err := r.publishedOnly(tx, events)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		newVisitors, err := r.insertVisitors(tx, events)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Article").CreateInBatches(events, 500).Error; err != nil {
+			return err
+		}
+
+		type totals struct {
+			views   int
+			seconds int64
+		}
+		perArticle := make(map[uuid.UUID]*totals)
+		for _, event := range events {
+			t := perArticle[event.ArticleID]
+			if t == nil {
+				t = &totals{}
+				perArticle[event.ArticleID] = t
+			}
+			switch event.Type {
+			case model.AnalyticsView:
+				t.views++
+			case model.AnalyticsHeartbeat:
+				t.seconds += int64(event.Seconds)
+			}
+		}
+
+		for articleID, t := range perArticle {
+			err := tx.Exec(`INSERT INTO analytics (article_id, views, unique_visitors, total_time_spent, average_time_spent, created_at, updated_at)
+				VALUES (@article, @views, @visitors, @seconds, @average, NOW(), NOW())
+				ON CONFLICT (article_id) DO UPDATE SET
+					views = analytics.views + EXCLUDED.views,
+					unique_visitors = analytics.unique_visitors + EXCLUDED.unique_visitors,
+					total_time_spent = analytics.total_time_spent + EXCLUDED.total_time_spent,
+					average_time_spent = (analytics.total_time_spent + EXCLUDED.total_time_spent) / GREATEST(analytics.views + EXCLUDED.views, 1),
+					updated_at = NOW()`,
+				map[string]interface{}{
+					"article":  articleID,
+					"views":    t.views,
+					"visitors": newVisitors[articleID],
+					"seconds":  t.seconds,
+					"average":  t.seconds / int64(max(t.views, 1)),
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *analyticsRepository) publishedOnly(tx *gorm.DB, events []model.AnalyticsEvent) ([]model.AnalyticsEvent, error) {
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	for _, event := range events {
+		if !seen[event.ArticleID] {
+			seen[event.ArticleID] = true
+			ids = append(ids, event.ArticleID)
+		}
+	}
+
+	var published []uuid.UUID
+	err := tx.Model(&model.Article{}).
+		Where("id IN ? AND status = ?", ids, model.Published).
+		Pluck("id", &published).Error
+	if err != nil {
+		return nil, err
+	}
+
+	valid := make(map[uuid.UUID]bool, len(published))
+	for _, id := range published {
+		valid[id] = true
+	}
+
+	kept := make([]model.AnalyticsEvent, 0, len(events))
+	for _, event := range events {
+		if valid[event.ArticleID] {
+			kept = append(kept, event)
+		}
+	}
+	return kept, nil
+}
+
+// insertVisitors records the visitors of the view events and returns, per
+// article, how many of them had not been seen that day.
+func (r *analyticsRepository) insertVisitors(tx *gorm.DB, events []model.AnalyticsEvent) (map[uuid.UUID]int, error) {
+	seen := make(map[model.AnalyticsVisitor]bool)
+	var visitors []model.AnalyticsVisitor
+	for _, event := range events {
+		if event.Type != model.AnalyticsView {
+			continue
+		}
+		visitor := model.AnalyticsVisitor{
+			ArticleID:   event.ArticleID,
+			Day:         event.CreatedAt.UTC().Truncate(24 * time.Hour),
+			VisitorHash: event.VisitorHash,
+		}
+		if !seen[visitor] {
+			seen[visitor] = true
+			visitors = append(visitors, visitor)
+		}
+	}
+
+	counts := make(map[uuid.UUID]int)
+	for start := 0; start < len(visitors); start += visitorInsertChunk {
+		chunk := visitors[start:min(start+visitorInsertChunk, len(visitors))]
+
+		placeholders := make([]string, 0, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*3)
+		for _, visitor := range chunk {
+			placeholders = append(placeholders, "(?, ?, ?)")
+			args = append(args, visitor.ArticleID, visitor.Day, visitor.VisitorHash)
+		}
+
+		var inserted []uuid.UUID
+		err := tx.Raw(`INSERT INTO analytics_visitors (article_id, day, visitor_hash) VALUES `+
+			strings.Join(placeholders, ", ")+` ON CONFLICT DO NOTHING RETURNING article_id`, args...).
+			Scan(&inserted).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, articleID := range inserted {
+			counts[articleID]++
+		}
+	}
+	return counts, nil
+}
+
+// LastRollupBucket returns the start of the latest hourly bucket rolled up,
+// or nil when nothing has been rolled up yet.
+func (r *analyticsRepository) LastRollupBucket() (*time.Time, error) {
+	var last sql.NullTime
+	err := r.db.Model(&model.AnalyticsRollup{}).
+		Where("granularity = ?", model.AnalyticsHourly).
+		Select("MAX(bucket_start)").
+		Scan(&last).Error
+	if err != nil || !last.Valid {
+		return nil, err
+	}
+	return &last.Time, nil
+}
+
+// RollupEvents recomputes every hourly and daily bucket, and the daily
+// source counts, from the raw events since the given time. Buckets are
+// overwritten rather than added to, so the same range can be rolled up
+// again safely. since must fall on a UTC day boundary for the daily
+// buckets to be complete.
+func (r *analyticsRepository) RollupEvents(since time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, granularity := range []model.AnalyticsGranularity{model.AnalyticsHourly, model.AnalyticsDaily} {
+			err := tx.Exec(`INSERT INTO analytics_rollups (article_id, granularity, bucket_start, views, unique_visitors, total_time_spent)
+				SELECT article_id, @granularity, date_trunc(@granularity, created_at, 'UTC'),
+					COUNT(*) FILTER (WHERE type = @view),
+					COUNT(DISTINCT visitor_hash) FILTER (WHERE type = @view),
+					COALESCE(SUM(seconds), 0)
+				FROM analytics_events
+				WHERE created_at >= @since
+				GROUP BY 1, 3
+				ON CONFLICT (article_id, granularity, bucket_start) DO UPDATE SET
+					views = EXCLUDED.views,
+					unique_visitors = EXCLUDED.unique_visitors,
+					total_time_spent = EXCLUDED.total_time_spent`,
+				map[string]interface{}{
+					"granularity": string(granularity),
+					"view":        model.AnalyticsView,
+					"since":       since,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec(`INSERT INTO analytics_source_rollups (article_id, day, referrer_domain, utm_source, utm_medium, utm_campaign, source_type, source_name, views)
+			SELECT article_id, (created_at AT TIME ZONE 'UTC')::date,
+				COALESCE(referrer_domain, ''), COALESCE(utm_source, ''), COALESCE(utm_medium, ''), COALESCE(utm_campaign, ''),
+				COALESCE((array_agg(source_type ORDER BY created_at DESC))[1], ''),
+				COALESCE((array_agg(source_name ORDER BY created_at DESC))[1], ''),
+				COUNT(*)
+			FROM analytics_events
+			WHERE type = @view AND created_at >= @since
+			GROUP BY 1, 2, 3, 4, 5, 6
+			ON CONFLICT (article_id, day, referrer_domain, utm_source, utm_medium, utm_campaign) DO UPDATE SET
+				source_type = EXCLUDED.source_type,
+				source_name = EXCLUDED.source_name,
+				views = EXCLUDED.views`,
+			map[string]interface{}{
+				"view":  model.AnalyticsView,
+				"since": since,
+			}).Error
+	})
+}
+
+// PurgeEvents deletes raw events older than before, a chunk at a time.
+func (r *analyticsRepository) PurgeEvents(before time.Time) (int64, error) {
+	var total int64
+	for {
+		result := r.db.Exec(`DELETE FROM analytics_events WHERE id IN (
+			SELECT id FROM analytics_events WHERE created_at < ? LIMIT ?)`, before, eventPurgeChunk)
+		if result.Error != nil {
+			return total, result.Error
+		}
+
+		total += result.RowsAffected
+		if result.RowsAffected < eventPurgeChunk {
+			return total, nil
+		}
+	}
+}
+
+// PurgeHourlyRollups deletes hourly buckets older than before. Daily
+// buckets are kept for good.
+func (r *analyticsRepository) PurgeHourlyRollups(before time.Time) (int64, error) {
+	result := r.db.
+		Where("granularity = ? AND bucket_start < ?", model.AnalyticsHourly, before).
+		Delete(&model.AnalyticsRollup{})
+	return result.RowsAffected, result.Error
+}
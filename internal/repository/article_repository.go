@@ -1,16 +1,264 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// articleSortColumns whitelists the columns listings may be ordered by;
+// the value tells whether the column can be NULL.
+var articleSortColumns = map[string]bool{
+	"published_at": true,
+	"created_at":   false,
+	"updated_at":   false,
+	"title":        false,
+}
+
+// ArticleListParams filters and orders an article listing. Nil and empty
+// filters are ignored. From and To apply to DateColumn, To is exclusive.
+// Rows are ordered by SortColumn with NULLs last, then by ID.
+type ArticleListParams struct {
+	Statuses   []model.ArticleStatus
+	AuthorID   *uuid.UUID
+	CategoryID *uuid.UUID
+	TagID      *uuid.UUID
+	DateColumn string
+	From       *time.Time
+	To         *time.Time
+	SortColumn string
+	Descending bool
+	After      *ArticleKey
+	Limit      int
+}
+
+// ArticleKey is the position of an article in a listing: its value in the
+// sort column, nil for NULL, and its ID to break ties. A listing continues
+// strictly after the key, which keeps pages stable under concurrent inserts.
+type ArticleKey struct {
+	Value interface{}
+	ID    uuid.UUID
+}
+
 type ArticleRepository interface {
-	CreateArticle(article model.Article) error
-	GetArticleByID(id string) (model.Article, error)
-	GetArticleByAuthor(authorID uuid.UUID) ([]model.Article, error)
-	GetArticleBySlug(slug string) ([]model.Article, error)
-	UpdateArticle(article model.Article) error
-	ListAllArticle(status string, limit, offset int) ([]model.Article, error)
-	DeleteArticle(id string) error
+	CreateArticle(article *model.Article) (*model.Article, error)
+	GetArticleByID(id uuid.UUID) (*model.Article, error)
+	GetArticleBySlug(slug string) (*model.Article, error)
+	ListSlugsWithPrefix(prefix string) ([]string, error)
+	UpdateArticle(article *model.Article) error
+	ListArticles(params ArticleListParams) ([]model.Article, error)
+	DeleteArticle(id uuid.UUID) error
+	ListPublishedArticles(categoryID, tagID *uuid.UUID, limit int) ([]model.Article, error)
+	ListSitemapEntries(limit, offset int) ([]model.Article, error)
+	ListSitemapPageLastMods(pageSize int) ([]time.Time, error)
+}
+
+type articleRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleRepository(db *gorm.DB) ArticleRepository {
+	return &articleRepository{
+		db: db,
+	}
+}
+
+func (r *articleRepository) CreateArticle(article *model.Article) (*model.Article, error) {
+	err := r.db.Create(article).Error
+	if err != nil {
+		return nil, slugConflict(err)
+	}
+
+	return article, nil
+}
+
+func (r *articleRepository) GetArticleByID(id uuid.UUID) (*model.Article, error) {
+	var article model.Article
+	err := r.db.Preload("Author").Where("id = ?", id).First(&article).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &article, err
+}
+
+func (r *articleRepository) GetArticleBySlug(slug string) (*model.Article, error) {
+	var article model.Article
+	err := r.db.Preload("Author").Where("slug = ?", slug).First(&article).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &article, err
+}
+
+func (r *articleRepository) ListSlugsWithPrefix(prefix string) ([]string, error) {
+	var slugs []string
+	err := r.db.Model(&model.Article{}).
+		Where("slug LIKE ?", escapeLike(prefix)+"%").
+		Pluck("slug", &slugs).Error
+	return slugs, err
+}
+
+// UpdateArticle writes the editable fields only. Status, published_at and
+// scheduled_at belong to the workflow, so an edit based on an older read
+// cannot undo a publication made in the meantime.
+//
+// When the title or content changes, the stored ones are kept as a new
+// version first. The row is locked from reading them until the update, so
+// concurrent edits each snapshot the content the other one left behind.
+func (r *articleRepository) UpdateArticle(article *model.Article) error {
+	return slugConflict(r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.Article
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "title", "content").
+			Where("id = ?", article.ID).
+			First(&current).Error
+		if err != nil {
+			return err
+		}
+
+		if current.Title != article.Title || current.Content != article.Content {
+			err := createArticleVersion(tx, &model.ArticleVersion{
+				ArticleID: current.ID,
+				Title:     current.Title,
+				Content:   current.Content,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(article).Select("title", "content", "slug").Updates(article).Error
+	}))
+}
+
+// slugConflict turns a unique index violation on the slug into
+// ErrSlugAlreadyExists, for writes that raced another one to the same slug.
+func slugConflict(err error) error {
+	if constraint, ok := uniqueViolation(err); ok && strings.Contains(constraint, "slug") {
+		return model.ErrSlugAlreadyExists
+	}
+	return err
+}
+
+// ListArticles returns one page of a keyset paginated listing.
+func (r *articleRepository) ListArticles(params ArticleListParams) ([]model.Article, error) {
+	nullable, ok := articleSortColumns[params.SortColumn]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort column %q", params.SortColumn)
+	}
+	if _, ok := articleSortColumns[params.DateColumn]; !ok && (params.From != nil || params.To != nil) {
+		return nil, fmt.Errorf("unsupported date column %q", params.DateColumn)
+	}
+
+	query := r.db.Preload("Author")
+	if len(params.Statuses) > 0 {
+		query = query.Where("status IN ?", params.Statuses)
+	}
+	if params.AuthorID != nil {
+		query = query.Where("author_id = ?", *params.AuthorID)
+	}
+	if params.CategoryID != nil {
+		query = query.Where("id IN ("+articlesInCategorySubtree+")", sql.Named("category", *params.CategoryID))
+	}
+	if params.TagID != nil {
+		query = query.Where("id IN (?)", r.db.Table("article_tags").Select("article_id").Where("tag_id = ?", *params.TagID))
+	}
+	if params.From != nil {
+		query = query.Where(params.DateColumn+" >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where(params.DateColumn+" < ?", *params.To)
+	}
+
+	column, direction, op := params.SortColumn, "ASC", ">"
+	if params.Descending {
+		direction, op = "DESC", "<"
+	}
+
+	if key := params.After; key != nil {
+		switch {
+		case key.Value == nil:
+			// only NULLs sort after a NULL
+			query = query.Where(column+" IS NULL AND id "+op+" ?", key.ID)
+		case nullable:
+			query = query.Where("("+column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?) OR "+column+" IS NULL)",
+				key.Value, key.Value, key.ID)
+		default:
+			query = query.Where("("+column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?))",
+				key.Value, key.Value, key.ID)
+		}
+	}
+
+	var articles []model.Article
+	err := query.Order(column + " " + direction + " NULLS LAST, id " + direction).
+		Limit(params.Limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+func (r *articleRepository) DeleteArticle(id uuid.UUID) error {
+	return r.db.Delete(&model.Article{}, "id = ?", id).Error
+}
+
+// ListPublishedArticles returns the newest published articles, optionally
+// limited to one category and its subcategories, or to one tag.
+func (r *articleRepository) ListPublishedArticles(categoryID, tagID *uuid.UUID, limit int) ([]model.Article, error) {
+	var articles []model.Article
+	query := r.db.Preload("Author").Preload("Category").Preload("Tags").
+		Where("status = ?", model.Published)
+	if categoryID != nil {
+		query = query.Where("id IN ("+articlesInCategorySubtree+")", sql.Named("category", *categoryID))
+	}
+	if tagID != nil {
+		query = query.Where("id IN (?)", r.db.Table("article_tags").Select("article_id").Where("tag_id = ?", *tagID))
+	}
+
+	err := query.Order("published_at DESC NULLS LAST, created_at DESC").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+// ListSitemapEntries pages through published articles oldest first, so new
+// articles only ever extend the last sitemap page.
+func (r *articleRepository) ListSitemapEntries(limit, offset int) ([]model.Article, error) {
+	var articles []model.Article
+	err := r.db.Select("id", "slug", "published_at", "updated_at").
+		Where("status = ?", model.Published).
+		Order("published_at ASC NULLS FIRST, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&articles).Error
+	return articles, err
+}
+
+// ListSitemapPageLastMods returns the latest UpdatedAt per sitemap page, in
+// page order. Row numbers start at 1 because the first page also lists the
+// home page.
+func (r *articleRepository) ListSitemapPageLastMods(pageSize int) ([]time.Time, error) {
+	var rows []struct {
+		Page    int
+		LastMod time.Time
+	}
+	err := r.db.Raw(`SELECT page, MAX(updated_at) AS last_mod FROM (
+			SELECT updated_at, ROW_NUMBER() OVER (ORDER BY published_at ASC NULLS FIRST, id ASC) / ? AS page
+			FROM articles WHERE status = ?
+		) AS pages GROUP BY page ORDER BY page`, pageSize, model.Published).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	lastMods := make([]time.Time, len(rows))
+	for i, row := range rows {
+		lastMods[i] = row.LastMod
+	}
+	return lastMods, nil
 }
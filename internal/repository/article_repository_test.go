@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestListArticlesKeyset(t *testing.T) {
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	published := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		sort       string
+		descending bool
+		after      *ArticleKey
+		want       string
+		wantOrder  string
+	}{
+		{
+			name:       "after a NULL only NULLs remain",
+			sort:       "published_at",
+			descending: true,
+			after:      &ArticleKey{ID: id},
+			want:       "published_at IS NULL AND id < '" + id.String() + "'",
+			wantOrder:  "ORDER BY published_at DESC NULLS LAST, id DESC",
+		},
+		{
+			name:      "a nullable column keeps the NULLs for last",
+			sort:      "published_at",
+			after:     &ArticleKey{Value: published, ID: id},
+			want:      "(published_at > '2024-03-01 00:00:00' OR (published_at = '2024-03-01 00:00:00' AND id > '" + id.String() + "') OR published_at IS NULL)",
+			wantOrder: "ORDER BY published_at ASC NULLS LAST, id ASC",
+		},
+		{
+			name:       "a column without NULLs",
+			sort:       "title",
+			descending: true,
+			after:      &ArticleKey{Value: "Go", ID: id},
+			want:       "(title < 'Go' OR (title = 'Go' AND id < '" + id.String() + "'))",
+			wantOrder:  "ORDER BY title DESC NULLS LAST, id DESC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, recorder := newDryRunDB(t)
+			_, err := NewArticleRepository(db).ListArticles(ArticleListParams{
+				SortColumn: tt.sort,
+				Descending: tt.descending,
+				After:      tt.after,
+				Limit:      11,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sql := recorder.last(t)
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("SQL %q does not contain %q", sql, tt.want)
+			}
+			if !strings.Contains(sql, tt.wantOrder+" LIMIT 11") {
+				t.Errorf("SQL %q is not ordered by %q", sql, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestListArticlesRejectsUnknownColumns(t *testing.T) {
+	db, _ := newDryRunDB(t)
+	repo := NewArticleRepository(db)
+	from := time.Now()
+
+	if _, err := repo.ListArticles(ArticleListParams{SortColumn: "id; DROP TABLE articles"}); err == nil {
+		t.Error("unknown sort column accepted")
+	}
+	if _, err := repo.ListArticles(ArticleListParams{SortColumn: "title", DateColumn: "deleted_at", From: &from}); err == nil {
+		t.Error("unknown date column accepted")
+	}
+}
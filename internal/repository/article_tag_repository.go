@@ -1 +1,106 @@
 package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
+
+// ArticleTagRepository manages the article_tags join table.
+type ArticleTagRepository interface {
+	ListTagsByArticle(articleID uuid.UUID) ([]model.Tag, error)
+	ReplaceArticleTags(articleID uuid.UUID, tagIDs []uuid.UUID) error
+	MergeTags(targetID uuid.UUID, sourceIDs []uuid.UUID) error
+	CountPublishedArticlesPerTag() (map[uuid.UUID]int64, error)
+	ListTaggedArticles(excludeID uuid.UUID, limit int) ([]model.Article, error)
+}
+
+type articleTagRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleTagRepository(db *gorm.DB) ArticleTagRepository {
+	return &articleTagRepository{
+		db: db,
+	}
+}
+
+func (r *articleTagRepository) ListTagsByArticle(articleID uuid.UUID) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.Where("id IN (?)", r.db.Table("article_tags").Select("tag_id").Where("article_id = ?", articleID)).
+		Order("name ASC").
+		Find(&tags).Error
+	return tags, err
+}
+
+func (r *articleTagRepository) ReplaceArticleTags(articleID uuid.UUID, tagIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM article_tags WHERE article_id = ?", articleID).Error; err != nil {
+			return err
+		}
+
+		for _, tagID := range tagIDs {
+			if err := tx.Exec("INSERT INTO article_tags (article_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+				articleID, tagID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MergeTags re-points every article of the source tags to the target tag
+// and deletes the source tags. Articles that already carry the target tag
+// keep a single link.
+func (r *articleTagRepository) MergeTags(targetID uuid.UUID, sourceIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO article_tags (article_id, tag_id)
+			SELECT DISTINCT article_id, ? FROM article_tags WHERE tag_id IN ?
+			ON CONFLICT DO NOTHING`, targetID, sourceIDs).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM article_tags WHERE tag_id IN ?", sourceIDs).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&model.Tag{}, "id IN ?", sourceIDs).Error
+	})
+}
+
+func (r *articleTagRepository) CountPublishedArticlesPerTag() (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		TagID uuid.UUID
+		Count int64
+	}
+	err := r.db.Raw(`SELECT atg.tag_id, COUNT(*) AS count
+		FROM article_tags atg
+		JOIN articles a ON a.id = atg.article_id AND a.status = ?
+		GROUP BY atg.tag_id`, model.Published).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TagID] = row.Count
+	}
+	return counts, nil
+}
+
+// ListTaggedArticles returns the newest published articles that carry at
+// least one tag, with their tags loaded. Only the columns needed to compare
+// content are selected.
+func (r *articleTagRepository) ListTaggedArticles(excludeID uuid.UUID, limit int) ([]model.Article, error) {
+	var articles []model.Article
+	err := r.db.Select("id", "title", "content").
+		Preload("Tags").
+		Where("status = ? AND id <> ?", model.Published, excludeID).
+		Where("id IN (?)", r.db.Table("article_tags").Select("article_id")).
+		Order("published_at DESC NULLS LAST, created_at DESC").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
@@ -1,7 +1,58 @@
 package repository
 
-import "github.com/tsaqiffatih/minddrift-server/internal/model"
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
 
 type ArticleVersionRepository interface {
-	CreateArticleVersion(articleVersion model.ArticleVersion) (model.ArticleVersion, error)
+	GetArticleVersionByID(id uuid.UUID) (*model.ArticleVersion, error)
+	ListArticleVersions(articleID uuid.UUID) ([]model.ArticleVersion, error)
+}
+
+type articleVersionRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleVersionRepository(db *gorm.DB) ArticleVersionRepository {
+	return &articleVersionRepository{
+		db: db,
+	}
+}
+
+func (r *articleVersionRepository) GetArticleVersionByID(id uuid.UUID) (*model.ArticleVersion, error) {
+	var articleVersion model.ArticleVersion
+	err := r.db.Where("id = ?", id).First(&articleVersion).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &articleVersion, err
+}
+
+func (r *articleVersionRepository) ListArticleVersions(articleID uuid.UUID) ([]model.ArticleVersion, error) {
+	var articleVersions []model.ArticleVersion
+	err := r.db.Where("article_id = ?", articleID).
+		Order("version DESC").
+		Find(&articleVersions).Error
+	return articleVersions, err
+}
+
+// createArticleVersion numbers a snapshot after the latest existing version
+// of the same article. The caller holds the lock on the article row, which
+// keeps concurrent snapshots from taking the same number.
+func createArticleVersion(tx *gorm.DB, articleVersion *model.ArticleVersion) error {
+	var latest int
+	err := tx.Model(&model.ArticleVersion{}).
+		Where("article_id = ?", articleVersion.ArticleID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+
+	articleVersion.Version = latest + 1
+	return tx.Omit("Article").Create(articleVersion).Error
 }
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// schedulerLockKey is the Postgres advisory lock key held by whichever
+// server instance is currently publishing scheduled articles.
+const schedulerLockKey int64 = 7_305_001
+
+type ArticleWorkflowRepository interface {
+	TransitionArticle(article *model.Article, history *model.ArticleStatusHistory) error
+	ListStatusHistory(articleID uuid.UUID) ([]model.ArticleStatusHistory, error)
+	PublishDueArticles(now time.Time) ([]model.Article, error)
+}
+
+type articleWorkflowRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleWorkflowRepository(db *gorm.DB) ArticleWorkflowRepository {
+	return &articleWorkflowRepository{
+		db: db,
+	}
+}
+
+// TransitionArticle saves the new article status and its history entry in
+// a single transaction. The update only applies while the article still has
+// the status it moves from, otherwise ErrStatusChanged is returned and no
+// history is recorded.
+func (r *articleWorkflowRepository) TransitionArticle(article *model.Article, history *model.ArticleStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return r.transition(tx, article, history)
+	})
+}
+
+func (r *articleWorkflowRepository) transition(tx *gorm.DB, article *model.Article, history *model.ArticleStatusHistory) error {
+	result := tx.Model(&model.Article{}).
+		Where("id = ? AND status = ?", article.ID, history.FromStatus).
+		Updates(map[string]interface{}{
+			"status":       article.Status,
+			"published_at": article.PublishedAt,
+			"scheduled_at": article.ScheduledAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrStatusChanged
+	}
+
+	return tx.Omit("Article", "Actor").Create(history).Error
+}
+
+func (r *articleWorkflowRepository) ListStatusHistory(articleID uuid.UUID) ([]model.ArticleStatusHistory, error) {
+	var histories []model.ArticleStatusHistory
+	err := r.db.Preload("Actor").
+		Where("article_id = ?", articleID).
+		Order("created_at ASC").
+		Find(&histories).Error
+	return histories, err
+}
+
+// PublishDueArticles publishes every scheduled article whose scheduled_at has
+// passed. It returns without doing anything when another instance holds the
+// scheduler advisory lock, and rows are locked with SKIP LOCKED so an article
+// can never be published twice.
+func (r *articleWorkflowRepository) PublishDueArticles(now time.Time) ([]model.Article, error) {
+	var articles []model.Article
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", schedulerLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+
+		if !locked {
+			return nil
+		}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND scheduled_at <= ?", model.Scheduled, now).
+			Find(&articles).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range articles {
+			article := &articles[i]
+
+			// The publication is attributed to whoever scheduled the article.
+			var scheduled model.ArticleStatusHistory
+			err := tx.Where("article_id = ? AND to_status = ?", article.ID, model.Scheduled).
+				Order("created_at DESC").
+				First(&scheduled).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			actorID := scheduled.ActorID
+			if actorID == uuid.Nil {
+				actorID = article.AuthorID
+			}
+
+			article.Status = model.Published
+			article.PublishedAt = article.ScheduledAt
+			article.ScheduledAt = nil
+
+			history := &model.ArticleStatusHistory{
+				ArticleID:  article.ID,
+				FromStatus: model.Scheduled,
+				ToStatus:   model.Published,
+				ActorID:    actorID,
+				Reason:     "Scheduled publication",
+			}
+
+			if err := r.transition(tx, article, history); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
@@ -1 +1,232 @@
 package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
+
+// articlesInCategorySubtree selects the IDs of articles filed under the
+// category bound to @category or under any of its subcategories.
+const articlesInCategorySubtree = `SELECT ac.article_id FROM article_categories ac
+	WHERE ac.category_id IN (
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = @category AND deleted_at IS NULL
+			UNION
+			SELECT c.id FROM categories c JOIN subtree ON c.parent_id = subtree.id
+			WHERE c.deleted_at IS NULL
+		)
+		SELECT id FROM subtree
+	)`
+
+// categoryAncestors selects the IDs of the ancestors of the category bound
+// to @category. UNION stops the walk on a corrupted cycle.
+const categoryAncestors = `WITH RECURSIVE ancestors AS (
+		SELECT parent_id AS id FROM categories WHERE id = @category
+		UNION
+		SELECT c.parent_id FROM categories c JOIN ancestors ON c.id = ancestors.id
+		WHERE c.parent_id IS NOT NULL AND c.deleted_at IS NULL
+	)
+	SELECT id FROM ancestors`
+
+type CategoryRepository interface {
+	CreateCategory(category *model.Category) error
+	GetCategoryByID(id uuid.UUID) (*model.Category, error)
+	GetCategoryBySlug(slug string) (*model.Category, error)
+	GetCategoryByName(name string) (*model.Category, error)
+	GetCategoriesByIDs(ids []uuid.UUID) ([]model.Category, error)
+	ListCategories() ([]model.Category, error)
+	ListCategoryAncestors(id uuid.UUID) ([]model.Category, error)
+	ListChildCategories(parentID uuid.UUID) ([]model.Category, error)
+	ListSlugsWithPrefix(prefix string) ([]string, error)
+	UpdateCategory(category *model.Category) error
+	DeleteCategory(id uuid.UUID) error
+	CountPublishedArticles(categoryIDs []uuid.UUID) (direct, total map[uuid.UUID]int64, err error)
+
+	ListCategoriesByArticle(articleID uuid.UUID) ([]model.Category, error)
+	ReplaceArticleCategories(articleID uuid.UUID, categoryIDs []uuid.UUID) error
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{
+		db: db,
+	}
+}
+
+func (r *categoryRepository) CreateCategory(category *model.Category) error {
+	return r.db.Omit("Parent", "Articles").Create(category).Error
+}
+
+func (r *categoryRepository) GetCategoryByID(id uuid.UUID) (*model.Category, error) {
+	return r.findCategory(r.db.Where("id = ?", id))
+}
+
+func (r *categoryRepository) GetCategoryBySlug(slug string) (*model.Category, error) {
+	return r.findCategory(r.db.Where("slug = ?", slug))
+}
+
+// GetCategoryByName matches case-insensitively.
+func (r *categoryRepository) GetCategoryByName(name string) (*model.Category, error) {
+	return r.findCategory(r.db.Where("LOWER(name) = LOWER(?)", name))
+}
+
+func (r *categoryRepository) findCategory(query *gorm.DB) (*model.Category, error) {
+	var category model.Category
+	err := query.First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &category, err
+}
+
+func (r *categoryRepository) GetCategoriesByIDs(ids []uuid.UUID) ([]model.Category, error) {
+	var categories []model.Category
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	err := r.db.Where("id IN ?", ids).Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) ListCategories() ([]model.Category, error) {
+	var categories []model.Category
+	err := r.db.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) ListCategoryAncestors(id uuid.UUID) ([]model.Category, error) {
+	var categories []model.Category
+	err := r.db.Where("id IN ("+categoryAncestors+")", sql.Named("category", id)).Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) ListChildCategories(parentID uuid.UUID) ([]model.Category, error) {
+	var categories []model.Category
+	err := r.db.Where("parent_id = ?", parentID).Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) ListSlugsWithPrefix(prefix string) ([]string, error) {
+	var slugs []string
+	err := r.db.Unscoped().Model(&model.Category{}).
+		Where("slug LIKE ?", escapeLike(prefix)+"%").
+		Pluck("slug", &slugs).Error
+	return slugs, err
+}
+
+func (r *categoryRepository) UpdateCategory(category *model.Category) error {
+	return r.db.Omit("Parent", "Articles").Save(category).Error
+}
+
+// DeleteCategory removes the category for good, freeing its name and slug,
+// and moves its children up to its own parent.
+func (r *categoryRepository) DeleteCategory(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var category model.Category
+		if err := tx.Where("id = ?", id).First(&category).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.Category{}).
+			Where("parent_id = ?", id).
+			Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&model.Category{}, "id = ?", id).Error
+	})
+}
+
+// CountPublishedArticles returns, per category, the number of published
+// articles filed directly under it and the number of distinct published
+// articles in its whole subtree. A nil categoryIDs counts every category.
+func (r *categoryRepository) CountPublishedArticles(categoryIDs []uuid.UUID) (map[uuid.UUID]int64, map[uuid.UUID]int64, error) {
+	type row struct {
+		CategoryID uuid.UUID
+		Count      int64
+	}
+
+	directFilter, rootFilter := "", ""
+	var filterArgs []interface{}
+	if categoryIDs != nil {
+		directFilter, rootFilter = "WHERE ac.category_id IN ?", "AND id IN ?"
+		filterArgs = append(filterArgs, categoryIDs)
+	}
+
+	var directRows []row
+	err := r.db.Raw(`SELECT ac.category_id, COUNT(*) AS count
+		FROM article_categories ac
+		JOIN articles a ON a.id = ac.article_id AND a.status = ?
+		`+directFilter+`
+		GROUP BY ac.category_id`, append([]interface{}{model.Published}, filterArgs...)...).
+		Scan(&directRows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// UNION (not UNION ALL) also stops the recursion on a corrupted cycle
+	var totalRows []row
+	err = r.db.Raw(`WITH RECURSIVE subtree AS (
+			SELECT id AS root_id, id FROM categories WHERE deleted_at IS NULL `+rootFilter+`
+			UNION
+			SELECT subtree.root_id, c.id FROM categories c
+			JOIN subtree ON c.parent_id = subtree.id
+			WHERE c.deleted_at IS NULL
+		)
+		SELECT subtree.root_id AS category_id, COUNT(DISTINCT ac.article_id) AS count
+		FROM subtree
+		JOIN article_categories ac ON ac.category_id = subtree.id
+		JOIN articles a ON a.id = ac.article_id AND a.status = ?
+		GROUP BY subtree.root_id`, append(filterArgs, model.Published)...).
+		Scan(&totalRows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	direct := make(map[uuid.UUID]int64, len(directRows))
+	for _, row := range directRows {
+		direct[row.CategoryID] = row.Count
+	}
+	total := make(map[uuid.UUID]int64, len(totalRows))
+	for _, row := range totalRows {
+		total[row.CategoryID] = row.Count
+	}
+	return direct, total, nil
+}
+
+func (r *categoryRepository) ListCategoriesByArticle(articleID uuid.UUID) ([]model.Category, error) {
+	var categories []model.Category
+	err := r.db.Where("id IN (?)", r.db.Table("article_categories").Select("category_id").Where("article_id = ?", articleID)).
+		Order("name ASC").
+		Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) ReplaceArticleCategories(articleID uuid.UUID, categoryIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM article_categories WHERE article_id = ?", articleID).Error; err != nil {
+			return err
+		}
+
+		for _, categoryID := range categoryIDs {
+			if err := tx.Exec("INSERT INTO article_categories (article_id, category_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+				articleID, categoryID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// escapeLike escapes LIKE wildcards so prefix matches literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -1,13 +1,151 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
 )
 
 type CommentRepository interface {
 	CreateComment(comment *model.Comment) error
 	GetCommentByID(id uuid.UUID) (*model.Comment, error)
 	GetCommentByArticle(articleID uuid.UUID) ([]model.Comment, error)
-	DeleteComent(id uuid.UUID) error
+	ListRootComments(articleID uuid.UUID, status model.CommentStatus, limit, offset int) ([]model.Comment, int64, error)
+	ListThreadReplies(rootIDs []uuid.UUID, status model.CommentStatus) ([]model.Comment, error)
+	ListCommentsByStatus(status model.CommentStatus, limit, offset int) ([]model.Comment, int64, error)
+	UpdateCommentStatus(id uuid.UUID, status model.CommentStatus) error
+	UpdateTrainedLabel(id uuid.UUID, label string) error
+	GetCommentByConfirmationHash(hash string) (*model.Comment, error)
+	ConfirmComment(id uuid.UUID, status model.CommentStatus) error
+	CountRecentCommentsByIP(ip string, since time.Time) (int64, error)
+	DeleteComment(id uuid.UUID) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{
+		db: db,
+	}
+}
+
+func (r *commentRepository) CreateComment(comment *model.Comment) error {
+	return r.db.Omit("Article", "User").Create(comment).Error
+}
+
+func (r *commentRepository) GetCommentByID(id uuid.UUID) (*model.Comment, error) {
+	var comment model.Comment
+	err := r.db.Preload("User").Preload("Article").Where("id = ?", id).First(&comment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &comment, err
+}
+
+func (r *commentRepository) GetCommentByArticle(articleID uuid.UUID) ([]model.Comment, error) {
+	var comments []model.Comment
+	err := r.db.Preload("User").
+		Where("article_id = ?", articleID).
+		Order("created_at ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
+// ListRootComments returns one page of top-level comments of an article,
+// newest first, together with the total number of top-level comments.
+func (r *commentRepository) ListRootComments(articleID uuid.UUID, status model.CommentStatus, limit, offset int) ([]model.Comment, int64, error) {
+	var comments []model.Comment
+	var total int64
+
+	query := r.db.Model(&model.Comment{}).
+		Where("article_id = ? AND parent_id IS NULL AND status = ?", articleID, status)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&comments).Error
+	return comments, total, err
+}
+
+// ListThreadReplies returns every reply, at any depth, in the given threads.
+func (r *commentRepository) ListThreadReplies(rootIDs []uuid.UUID, status model.CommentStatus) ([]model.Comment, error) {
+	var comments []model.Comment
+	if len(rootIDs) == 0 {
+		return comments, nil
+	}
+
+	err := r.db.Preload("User").
+		Where("root_id IN ? AND status = ?", rootIDs, status).
+		Order("created_at ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *commentRepository) ListCommentsByStatus(status model.CommentStatus, limit, offset int) ([]model.Comment, int64, error) {
+	var comments []model.Comment
+	var total int64
+
+	// guest comments still waiting for email confirmation are not moderated yet
+	query := r.db.Model(&model.Comment{}).
+		Where("status = ? AND (confirmation_hash IS NULL OR confirmation_hash = '')", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").
+		Preload("Article").
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&comments).Error
+	return comments, total, err
+}
+
+func (r *commentRepository) UpdateCommentStatus(id uuid.UUID, status model.CommentStatus) error {
+	return r.db.Model(&model.Comment{}).Where("id = ?", id).Update("status", status).Error
+}
+
+func (r *commentRepository) UpdateTrainedLabel(id uuid.UUID, label string) error {
+	return r.db.Model(&model.Comment{}).Where("id = ?", id).Update("trained_label", label).Error
+}
+
+func (r *commentRepository) GetCommentByConfirmationHash(hash string) (*model.Comment, error) {
+	var comment model.Comment
+	err := r.db.Where("confirmation_hash = ?", hash).First(&comment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &comment, err
+}
+
+// ConfirmComment marks a guest's email as confirmed and releases the comment
+// with the status its spam score earned.
+func (r *commentRepository) ConfirmComment(id uuid.UUID, status model.CommentStatus) error {
+	return r.db.Model(&model.Comment{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":             status,
+		"confirmation_hash":  "",
+		"email_confirmed_at": time.Now(),
+	}).Error
+}
+
+func (r *commentRepository) CountRecentCommentsByIP(ip string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Comment{}).
+		Where("ip_address = ? AND created_at >= ?", ip, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *commentRepository) DeleteComment(id uuid.UUID) error {
+	return r.db.Delete(&model.Comment{}, "id = ?", id).Error
 }
@@ -1 +1,115 @@
 package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
+
+type ImageRepository interface {
+	CreateImage(image *model.Image) error
+	GetImageByID(id uuid.UUID) (*model.Image, error)
+	GetImageByHash(hash string) (*model.Image, error)
+	GetUserImageByHash(userID uuid.UUID, hash string, articleID *uuid.UUID) (*model.Image, error)
+	CountImagesByHash(hash string) (int64, error)
+	ListImagesByUser(userID uuid.UUID, limit, offset int) ([]model.Image, int64, error)
+	ListImagesByArticle(articleID uuid.UUID, limit, offset int) ([]model.Image, int64, error)
+	UpdateImage(image *model.Image) error
+	DeleteImage(id uuid.UUID) error
+}
+
+type imageRepository struct {
+	db *gorm.DB
+}
+
+func NewImageRepository(db *gorm.DB) ImageRepository {
+	return &imageRepository{
+		db: db,
+	}
+}
+
+// CreateImage also creates the image's variants.
+func (r *imageRepository) CreateImage(image *model.Image) error {
+	return r.db.Omit("Article", "User").Create(image).Error
+}
+
+func (r *imageRepository) GetImageByID(id uuid.UUID) (*model.Image, error) {
+	var image model.Image
+	err := r.db.Preload("Variants", orderedVariants).Where("id = ?", id).First(&image).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &image, err
+}
+
+// GetImageByHash returns any image whose content matches hash, so a new
+// upload can reuse the stored object.
+func (r *imageRepository) GetImageByHash(hash string) (*model.Image, error) {
+	var image model.Image
+	err := r.db.Preload("Variants", orderedVariants).Where("content_hash = ?", hash).Order("created_at ASC").First(&image).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &image, err
+}
+
+func (r *imageRepository) GetUserImageByHash(userID uuid.UUID, hash string, articleID *uuid.UUID) (*model.Image, error) {
+	var image model.Image
+	query := r.db.Preload("Variants", orderedVariants).Where("uploaded_by = ? AND content_hash = ?", userID, hash)
+	if articleID != nil {
+		query = query.Where("article_id = ?", *articleID)
+	} else {
+		query = query.Where("article_id IS NULL")
+	}
+
+	err := query.First(&image).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &image, err
+}
+
+func (r *imageRepository) CountImagesByHash(hash string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Image{}).Where("content_hash = ?", hash).Count(&count).Error
+	return count, err
+}
+
+func (r *imageRepository) ListImagesByUser(userID uuid.UUID, limit, offset int) ([]model.Image, int64, error) {
+	return r.listImages(r.db.Where("uploaded_by = ?", userID), limit, offset)
+}
+
+func (r *imageRepository) ListImagesByArticle(articleID uuid.UUID, limit, offset int) ([]model.Image, int64, error) {
+	return r.listImages(r.db.Where("article_id = ?", articleID), limit, offset)
+}
+
+func (r *imageRepository) listImages(query *gorm.DB, limit, offset int) ([]model.Image, int64, error) {
+	var images []model.Image
+	var total int64
+
+	query = query.Model(&model.Image{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Variants", orderedVariants).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&images).Error
+	return images, total, err
+}
+
+func (r *imageRepository) UpdateImage(image *model.Image) error {
+	return r.db.Omit("Article", "User", "Variants").Save(image).Error
+}
+
+func (r *imageRepository) DeleteImage(id uuid.UUID) error {
+	return r.db.Delete(&model.Image{}, "id = ?", id).Error
+}
+
+func orderedVariants(db *gorm.DB) *gorm.DB {
+	return db.Order("width ASC")
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository interface {
+	ReplaceRecoveryCodes(userID uuid.UUID, hashes []string) error
+	UseRecoveryCode(userID uuid.UUID, hash string) (bool, error)
+	DeleteRecoveryCodes(userID uuid.UUID) error
+}
+
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{
+		db: db,
+	}
+}
+
+// ReplaceRecoveryCodes discards any previous codes of the user and stores
+// the new set.
+func (r *recoveryCodeRepository) ReplaceRecoveryCodes(userID uuid.UUID, hashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]model.RecoveryCode, 0, len(hashes))
+		for _, hash := range hashes {
+			codes = append(codes, model.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+
+		return tx.Omit("User").Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode marks an unused code as used and reports whether one matched.
+func (r *recoveryCodeRepository) UseRecoveryCode(userID uuid.UUID, hash string) (bool, error) {
+	result := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *recoveryCodeRepository) DeleteRecoveryCodes(userID uuid.UUID) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
+
+var errRefreshTokenAlreadyRotated = errors.New("refresh token already rotated")
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(token *model.RefreshToken) error
+	GetRefreshTokenByHash(hash string) (*model.RefreshToken, error)
+	RotateRefreshToken(old *model.RefreshToken, next *model.RefreshToken) (bool, error)
+	RevokeFamily(familyID uuid.UUID) error
+	RevokeAllForUser(userID uuid.UUID) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db: db,
+	}
+}
+
+func (r *refreshTokenRepository) CreateRefreshToken(token *model.RefreshToken) error {
+	return r.db.Omit("User").Create(token).Error
+}
+
+func (r *refreshTokenRepository) GetRefreshTokenByHash(hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.Preload("User").Where("token_hash = ?", hash).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &token, err
+}
+
+// RotateRefreshToken revokes old and stores next in one transaction. It
+// returns false when old was revoked concurrently, which callers must treat
+// as token reuse.
+func (r *refreshTokenRepository) RotateRefreshToken(old *model.RefreshToken, next *model.RefreshToken) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User").Create(next).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", old.ID).
+			Updates(map[string]interface{}{
+				"revoked_at":     time.Now(),
+				"replaced_by_id": next.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errRefreshTokenAlreadyRotated
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errRefreshTokenAlreadyRotated) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID uuid.UUID) error {
+	return r.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(userID uuid.UUID) error {
+	return r.db.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
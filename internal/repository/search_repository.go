@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
+
+// Highlighted words in search results are wrapped in these markers, which
+// do not occur in article text, so the service can escape the snippet
+// before turning them into HTML.
+const (
+	SearchHighlightStart = "⟦"
+	SearchHighlightStop  = "⟧"
+)
+
+// ArticleSearchParams filters a full-text search. Nil fields are ignored.
+// CategoryID also matches the category's subcategories; To is exclusive.
+type ArticleSearchParams struct {
+	Query      string
+	CategoryID *uuid.UUID
+	TagID      *uuid.UUID
+	AuthorID   *uuid.UUID
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+type ArticleSearchHit struct {
+	ID             uuid.UUID
+	Title          string
+	Slug           string
+	AuthorID       uuid.UUID
+	Username       string
+	PublishedAt    *time.Time
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+	Total          int64
+}
+
+type SearchRepository interface {
+	EnsureSearchVector() error
+	SearchArticles(params ArticleSearchParams) ([]ArticleSearchHit, int64, error)
+}
+
+// searchRepository searches articles through the articles.search_vector
+// column, a generated tsvector with the title weighted A and the content C.
+// Tag names are weighted B at query time, since a generated column cannot
+// read other tables. Candidates are the articles whose search_vector
+// matches, through the GIN index, plus the articles carrying a matching
+// tag, so only those are ranked.
+type searchRepository struct {
+	db       *gorm.DB
+	language string
+}
+
+func NewSearchRepository(db *gorm.DB, language string) SearchRepository {
+	return &searchRepository{
+		db:       db,
+		language: language,
+	}
+}
+
+// EnsureSearchVector creates the generated column and its GIN index, and
+// rebuilds them when the configured language changed since the last start.
+func (r *searchRepository) EnsureSearchVector() error {
+	var expression string
+	err := r.db.Raw(`SELECT COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+		FROM pg_attribute a
+		JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = 'articles'::regclass AND a.attname = 'search_vector' AND NOT a.attisdropped`).
+		Scan(&expression).Error
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(expression, fmt.Sprintf("'%s'::regconfig", r.language)) {
+		return nil
+	}
+
+	// the language is validated by the config, it cannot be a bind parameter in DDL
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`ALTER TABLE articles DROP COLUMN IF EXISTS search_vector`,
+			fmt.Sprintf(`ALTER TABLE articles ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+				setweight(to_tsvector('%[1]s'::regconfig, coalesce(title, '')), 'A') ||
+				setweight(to_tsvector('%[1]s'::regconfig, coalesce(content, '')), 'C')
+			) STORED`, r.language),
+			`CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN (search_vector)`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SearchArticles returns one page of published articles matching the
+// query, best match first, with the total number of matches. The total
+// is read from the page itself, so it is 0 past the last page.
+func (r *searchRepository) SearchArticles(params ArticleSearchParams) ([]ArticleSearchHit, int64, error) {
+	args := map[string]interface{}{
+		"language":  r.language,
+		"query":     params.Query,
+		"published": model.Published,
+		"limit":     params.Limit,
+		"offset":    params.Offset,
+		"snippet": fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "`,
+			SearchHighlightStart, SearchHighlightStop),
+		"title": fmt.Sprintf(`StartSel="%s", StopSel="%s", HighlightAll=true`,
+			SearchHighlightStart, SearchHighlightStop),
+	}
+
+	var filters []string
+	if params.CategoryID != nil {
+		filters = append(filters, `a.id IN (`+articlesInCategorySubtree+`)`)
+		args["category"] = *params.CategoryID
+	}
+	if params.TagID != nil {
+		filters = append(filters, `a.id IN (SELECT article_id FROM article_tags WHERE tag_id = @tag)`)
+		args["tag"] = *params.TagID
+	}
+	if params.AuthorID != nil {
+		filters = append(filters, `a.author_id = @author`)
+		args["author"] = *params.AuthorID
+	}
+	if params.From != nil {
+		filters = append(filters, `a.published_at >= @from`)
+		args["from"] = *params.From
+	}
+	if params.To != nil {
+		filters = append(filters, `a.published_at < @to`)
+		args["to"] = *params.To
+	}
+
+	where := ""
+	if len(filters) > 0 {
+		where = "AND " + strings.Join(filters, "\n\t\tAND ")
+	}
+
+	// HTML tags are removed before ts_headline so snippets never cut a tag in half
+	var hits []ArticleSearchHit
+	err := r.db.Raw(`WITH q AS (SELECT websearch_to_tsquery(CAST(@language AS regconfig), @query) AS query),
+		candidates AS (
+			SELECT a.id FROM articles a CROSS JOIN q WHERE a.search_vector @@ q.query
+			UNION
+			SELECT atg.article_id FROM tags t
+			CROSS JOIN q
+			JOIN article_tags atg ON atg.tag_id = t.id
+			WHERE t.deleted_at IS NULL AND to_tsvector(CAST(@language AS regconfig), t.name) @@ q.query
+		)
+		SELECT a.id, a.title, a.slug, a.author_id, u.username, a.published_at,
+			ts_rank(a.search_vector || coalesce(tv.vector, ''::tsvector), q.query, 1) AS rank,
+			ts_headline(CAST(@language AS regconfig), a.title, q.query, @title) AS title_highlight,
+			ts_headline(CAST(@language AS regconfig), regexp_replace(a.content, '<[^>]*>', ' ', 'g'), q.query, @snippet) AS snippet,
+			COUNT(*) OVER () AS total
+		FROM candidates c
+		JOIN articles a ON a.id = c.id
+		CROSS JOIN q
+		JOIN users u ON u.id = a.author_id
+		LEFT JOIN LATERAL (
+			SELECT setweight(to_tsvector(CAST(@language AS regconfig), string_agg(t.name, ' ')), 'B') AS vector
+			FROM article_tags atg
+			JOIN tags t ON t.id = atg.tag_id AND t.deleted_at IS NULL
+			WHERE atg.article_id = a.id
+		) tv ON true
+		WHERE a.status = @published
+		`+where+`
+		ORDER BY rank DESC, a.published_at DESC NULLS LAST, a.id
+		LIMIT @limit OFFSET @offset`, args).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if len(hits) > 0 {
+		total = hits[0].Total
+	}
+	return hits, total, nil
+}
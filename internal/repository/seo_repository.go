@@ -1 +1,59 @@
 package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SEORepository interface {
+	GetSEOMetadataByArticle(articleID uuid.UUID) (*model.SEOMetadata, error)
+	ListSEOMetadataByArticles(articleIDs []uuid.UUID) ([]model.SEOMetadata, error)
+	SaveSEOMetadata(metadata *model.SEOMetadata) error
+	DeleteSEOMetadata(articleID uuid.UUID) error
+}
+
+type seoRepository struct {
+	db *gorm.DB
+}
+
+func NewSEORepository(db *gorm.DB) SEORepository {
+	return &seoRepository{
+		db: db,
+	}
+}
+
+func (r *seoRepository) GetSEOMetadataByArticle(articleID uuid.UUID) (*model.SEOMetadata, error) {
+	var metadata model.SEOMetadata
+	err := r.db.Where("article_id = ?", articleID).First(&metadata).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &metadata, err
+}
+
+// SaveSEOMetadata creates the article's metadata or overwrites it.
+func (r *seoRepository) ListSEOMetadataByArticles(articleIDs []uuid.UUID) ([]model.SEOMetadata, error) {
+	var metadata []model.SEOMetadata
+	if len(articleIDs) == 0 {
+		return metadata, nil
+	}
+	err := r.db.Where("article_id IN ?", articleIDs).Find(&metadata).Error
+	return metadata, err
+}
+
+func (r *seoRepository) SaveSEOMetadata(metadata *model.SEOMetadata) error {
+	return r.db.Omit("Article").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "article_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"meta_title", "meta_description", "keywords", "updated_at"}),
+	}).Create(metadata).Error
+}
+
+// DeleteSEOMetadata removes the row for good so the article's unique index
+// is free for new metadata.
+func (r *seoRepository) DeleteSEOMetadata(articleID uuid.UUID) error {
+	return r.db.Unscoped().Delete(&model.SEOMetadata{}, "article_id = ?", articleID).Error
+}
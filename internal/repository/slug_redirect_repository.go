@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SlugRedirectRepository interface {
+	SaveSlugRedirect(oldSlug string, articleID uuid.UUID) error
+	GetSlugRedirect(oldSlug string) (*model.SlugRedirect, error)
+	DeleteSlugRedirect(oldSlug string) error
+}
+
+type slugRedirectRepository struct {
+	db *gorm.DB
+}
+
+func NewSlugRedirectRepository(db *gorm.DB) SlugRedirectRepository {
+	return &slugRedirectRepository{
+		db: db,
+	}
+}
+
+// SaveSlugRedirect points oldSlug at the article, replacing any previous
+// target of the same slug.
+func (r *slugRedirectRepository) SaveSlugRedirect(oldSlug string, articleID uuid.UUID) error {
+	redirect := model.SlugRedirect{
+		OldSlug:   oldSlug,
+		ArticleID: articleID,
+	}
+
+	return r.db.Omit("Article").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "old_slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"article_id", "created_at"}),
+	}).Create(&redirect).Error
+}
+
+func (r *slugRedirectRepository) GetSlugRedirect(oldSlug string) (*model.SlugRedirect, error) {
+	var redirect model.SlugRedirect
+	err := r.db.Preload("Article").Where("old_slug = ?", oldSlug).First(&redirect).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &redirect, err
+}
+
+func (r *slugRedirectRepository) DeleteSlugRedirect(oldSlug string) error {
+	return r.db.Where("old_slug = ?", oldSlug).Delete(&model.SlugRedirect{}).Error
+}
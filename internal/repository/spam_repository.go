@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SpamRepository interface {
+	GetTokenCounts(tokens []string) (map[string]model.SpamToken, error)
+	GetCorpusCounts() (spamDocs, hamDocs int, err error)
+	AdjustTraining(tokens []string, label string, delta int) error
+}
+
+type spamRepository struct {
+	db *gorm.DB
+}
+
+func NewSpamRepository(db *gorm.DB) SpamRepository {
+	return &spamRepository{
+		db: db,
+	}
+}
+
+func (r *spamRepository) GetTokenCounts(tokens []string) (map[string]model.SpamToken, error) {
+	counts := make(map[string]model.SpamToken, len(tokens))
+	if len(tokens) == 0 {
+		return counts, nil
+	}
+
+	var rows []model.SpamToken
+	if err := r.db.Where("token IN ?", tokens).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.Token] = row
+	}
+	return counts, nil
+}
+
+func (r *spamRepository) GetCorpusCounts() (int, int, error) {
+	var rows []model.SpamCorpus
+	if err := r.db.Find(&rows).Error; err != nil {
+		return 0, 0, err
+	}
+
+	var spamDocs, hamDocs int
+	for _, row := range rows {
+		switch row.Label {
+		case model.SpamLabel:
+			spamDocs = row.Documents
+		case model.HamLabel:
+			hamDocs = row.Documents
+		}
+	}
+	return spamDocs, hamDocs, nil
+}
+
+// AdjustTraining adds delta (1 to learn, -1 to forget) to the counts of
+// every token and to the document count of label.
+func (r *spamRepository) AdjustTraining(tokens []string, label string, delta int) error {
+	column := "ham_count"
+	if label == model.SpamLabel {
+		column = "spam_count"
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(tokens) > 0 {
+			rows := make([]model.SpamToken, 0, len(tokens))
+			for _, token := range tokens {
+				row := model.SpamToken{Token: token}
+				if label == model.SpamLabel {
+					row.SpamCount = max(delta, 0)
+				} else {
+					row.HamCount = max(delta, 0)
+				}
+				rows = append(rows, row)
+			}
+
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "token"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					column: gorm.Expr("GREATEST(spam_tokens."+column+" + ?, 0)", delta),
+				}),
+			}).Create(&rows).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "label"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"documents": gorm.Expr("GREATEST(spam_corpus.documents + ?, 0)", delta),
+			}),
+		}).Create(&model.SpamCorpus{Label: label, Documents: max(delta, 0)}).Error
+	})
+}
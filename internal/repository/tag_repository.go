@@ -1 +1,146 @@
 package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	CreateTag(tag *model.Tag) error
+	GetTagByID(id uuid.UUID) (*model.Tag, error)
+	GetTagBySlug(slug string) (*model.Tag, error)
+	GetTagByNameKey(key string) (*model.Tag, error)
+	GetTagsByIDs(ids []uuid.UUID) ([]model.Tag, error)
+	ListTags() ([]model.Tag, error)
+	ListTagsByNameKeyPatterns(patterns []string, limit int) ([]model.Tag, error)
+	ListTagsWithoutNameKey() ([]model.Tag, error)
+	ListSlugsWithPrefix(prefix string) ([]string, error)
+	SetTagNameKey(id uuid.UUID, key string) error
+	UpdateTag(tag *model.Tag) error
+	DeleteTag(id uuid.UUID) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+func (r *tagRepository) CreateTag(tag *model.Tag) error {
+	return tagConflict(r.db.Omit("Articles").Create(tag).Error)
+}
+
+func (r *tagRepository) GetTagByID(id uuid.UUID) (*model.Tag, error) {
+	return r.findTag(r.db.Where("id = ?", id))
+}
+
+func (r *tagRepository) GetTagBySlug(slug string) (*model.Tag, error) {
+	return r.findTag(r.db.Where("slug = ?", slug))
+}
+
+func (r *tagRepository) GetTagByNameKey(key string) (*model.Tag, error) {
+	return r.findTag(r.db.Where("name_key = ?", key))
+}
+
+func (r *tagRepository) findTag(query *gorm.DB) (*model.Tag, error) {
+	var tag model.Tag
+	err := query.First(&tag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &tag, err
+}
+
+func (r *tagRepository) GetTagsByIDs(ids []uuid.UUID) ([]model.Tag, error) {
+	var tags []model.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := r.db.Where("id IN ?", ids).Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) ListTags() ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+// ListTagsByNameKeyPatterns returns up to limit tags whose normalized key
+// matches any of the LIKE patterns, shortest key first.
+func (r *tagRepository) ListTagsByNameKeyPatterns(patterns []string, limit int) ([]model.Tag, error) {
+	var tags []model.Tag
+	if len(patterns) == 0 {
+		return tags, nil
+	}
+
+	match := r.db.Where("name_key LIKE ?", patterns[0])
+	for _, pattern := range patterns[1:] {
+		match = match.Or("name_key LIKE ?", pattern)
+	}
+	err := r.db.Where(match).Order("length(name_key) ASC, name ASC").Limit(limit).Find(&tags).Error
+	return tags, err
+}
+
+// ListTagsWithoutNameKey returns the tags created before normalized keys
+// were stored.
+func (r *tagRepository) ListTagsWithoutNameKey() ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.Where("name_key IS NULL").Order("created_at ASC").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) ListSlugsWithPrefix(prefix string) ([]string, error) {
+	var slugs []string
+	err := r.db.Unscoped().Model(&model.Tag{}).
+		Where("slug LIKE ?", escapeLike(prefix)+"%").
+		Pluck("slug", &slugs).Error
+	return slugs, err
+}
+
+func (r *tagRepository) SetTagNameKey(id uuid.UUID, key string) error {
+	err := r.db.Model(&model.Tag{}).Where("id = ?", id).Update("name_key", key).Error
+	return tagConflict(err)
+}
+
+func (r *tagRepository) UpdateTag(tag *model.Tag) error {
+	return tagConflict(r.db.Omit("Articles").Save(tag).Error)
+}
+
+// DeleteTag removes the tag for good so its name and slug can be reused.
+func (r *tagRepository) DeleteTag(id uuid.UUID) error {
+	return r.db.Unscoped().Delete(&model.Tag{}, "id = ?", id).Error
+}
+
+// tagConflict turns a unique index violation into the matching tag error,
+// so a concurrent write that lost the race reads like a duplicate.
+func tagConflict(err error) error {
+	constraint, ok := uniqueViolation(err)
+	switch {
+	case !ok:
+		return err
+	case strings.Contains(constraint, "slug"):
+		return model.ErrTagSlugExists
+	default:
+		return model.ErrTagNameExists
+	}
+}
+
+// uniqueViolation reports whether err is a unique index violation, and on
+// which constraint.
+func uniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
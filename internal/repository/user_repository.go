@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tsaqiffatih/minddrift-server/internal/model"
@@ -14,6 +15,9 @@ type UserRepository interface {
 	GetUserByID(id uuid.UUID) (*model.User, error)
 	UpdateUser(user *model.User) error
 	DeleteUser(id uuid.UUID) error
+	AdvanceTwoFAStep(id uuid.UUID, step int64) (bool, error)
+	ReserveTwoFAAttempt(id uuid.UUID, maxAttempts int, now, lockUntil time.Time) (bool, error)
+	ResetTwoFAFailures(id uuid.UUID) error
 }
 
 type userRepository struct {
@@ -60,3 +64,37 @@ func (r *userRepository) UpdateUser(user *model.User) error {
 func (r *userRepository) DeleteUser(id uuid.UUID) error {
 	return r.db.Delete(&model.User{}, id).Error
 }
+
+// AdvanceTwoFAStep records step as the last accepted TOTP step. It returns
+// false when the same or a later step was already accepted, so concurrent
+// requests cannot both redeem one code.
+func (r *userRepository) AdvanceTwoFAStep(id uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND two_fa_last_step < ?", id, step).
+		UpdateColumn("two_fa_last_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReserveTwoFAAttempt counts a second factor attempt before it is checked,
+// in the same statement that checks the lock, so parallel attempts cannot
+// all pass the check before any of them is counted. The maxAttempts-th
+// attempt locks the second factor until lockUntil and starts a new count.
+// It returns false while the second factor is locked.
+func (r *userRepository) ReserveTwoFAAttempt(id uuid.UUID, maxAttempts int, now, lockUntil time.Time) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND (two_fa_locked_until IS NULL OR two_fa_locked_until <= ?)", id, now).
+		UpdateColumns(map[string]interface{}{
+			"two_fa_locked_until": gorm.Expr("CASE WHEN two_fa_failed_count + 1 >= ? THEN CAST(? AS timestamptz) ELSE two_fa_locked_until END", maxAttempts, lockUntil),
+			"two_fa_failed_count": gorm.Expr("CASE WHEN two_fa_failed_count + 1 >= ? THEN 0 ELSE two_fa_failed_count + 1 END", maxAttempts),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *userRepository) ResetTwoFAFailures(id uuid.UUID) error {
+	return r.db.Model(&model.User{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"two_fa_failed_count": 0,
+			"two_fa_locked_until": nil,
+		}).Error
+}
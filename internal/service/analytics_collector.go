@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+)
+
+// maxBufferedBatches bounds the buffer while the database is unreachable;
+// hits beyond it are dropped rather than growing memory without limit.
+const maxBufferedBatches = 20
+
+// AnalyticsCollector buffers analytics hits in memory and writes them in
+// batches, when a batch is full or when the flush interval passes, so a
+// burst of traffic costs one transaction per batch instead of one write
+// per hit. Hits still buffered when the process dies are lost; on a
+// graceful shutdown, Wait returns once they are written.
+type AnalyticsCollector interface {
+	Start(ctx context.Context)
+	Record(event model.AnalyticsEvent)
+	Flush()
+	Wait()
+}
+
+type analyticsCollector struct {
+	repo      repository.AnalyticsRepository
+	interval  time.Duration
+	batchSize int
+
+	mu      sync.Mutex
+	pending []model.AnalyticsEvent
+	dropped int
+
+	flushMu sync.Mutex
+	full    chan struct{}
+	done    chan struct{}
+}
+
+func NewAnalyticsCollector(repo repository.AnalyticsRepository, interval time.Duration, batchSize int) AnalyticsCollector {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	return &analyticsCollector{
+		repo:      repo,
+		interval:  interval,
+		batchSize: batchSize,
+		full:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+}
+
+// **Start Collector**
+// Flushes in the background until ctx is cancelled, then flushes once more.
+func (c *analyticsCollector) Start(ctx context.Context) {
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				c.Flush()
+				return
+			case <-ticker.C:
+				c.Flush()
+			case <-c.full:
+				c.Flush()
+			}
+		}
+	}()
+}
+
+// **Wait For Collector**
+// Blocks until the collector started by Start has made its last flush.
+func (c *analyticsCollector) Wait() {
+	<-c.done
+}
+
+// **Record Hit**
+func (c *analyticsCollector) Record(event model.AnalyticsEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.pending) >= c.batchSize*maxBufferedBatches {
+		c.dropped++
+		return
+	}
+
+	c.pending = append(c.pending, event)
+	if len(c.pending) == c.batchSize {
+		select {
+		case c.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// **Flush Buffered Hits**
+// A failed batch goes back to the front of the buffer to be retried with
+// the next flush.
+func (c *analyticsCollector) Flush() {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	batch, dropped := c.pending, c.dropped
+	c.pending, c.dropped = nil, 0
+	c.mu.Unlock()
+
+	if dropped > 0 {
+		log.Printf("Analytics buffer full, dropped %d hits", dropped)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := c.repo.RecordEvents(batch); err != nil {
+		log.Println("Error recording analytics events:", err)
+
+		c.mu.Lock()
+		room := c.batchSize*maxBufferedBatches - len(c.pending)
+		if len(batch) > room {
+			c.dropped += len(batch) - room
+			batch = batch[len(batch)-room:]
+		}
+		c.pending = append(batch, c.pending...)
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+)
+
+// analyticsPreset is a dashboard range ending now, with the interval used
+// when the client does not ask for one.
+type analyticsPreset struct {
+	interval model.AnalyticsGranularity
+	length   time.Duration
+}
+
+var analyticsPresets = map[string]analyticsPreset{
+	"24h": {model.AnalyticsHourly, 24 * time.Hour},
+	"7d":  {model.AnalyticsDaily, 7 * 24 * time.Hour},
+	"30d": {model.AnalyticsDaily, 30 * 24 * time.Hour},
+	"90d": {model.AnalyticsDaily, 90 * 24 * time.Hour},
+	"12m": {model.AnalyticsDaily, 365 * 24 * time.Hour},
+}
+
+// **Analytics Overview**
+func (s *analyticsService) GetOverview(req dto.AnalyticsDashboardRequest, userID uuid.UUID, role model.UserRole) (*dto.AnalyticsOverviewResponse, error) {
+	query, err := s.reportQuery(req, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.reportRepo.Totals(query)
+	if err != nil {
+		log.Println("Error getting analytics totals:", err)
+		return nil, errors.New("Failed to get analytics")
+	}
+
+	previousQuery := query
+	previousQuery.From, previousQuery.To = query.From.Add(-query.To.Sub(query.From)), query.From
+	previous, err := s.reportRepo.Totals(previousQuery)
+	if err != nil {
+		log.Println("Error getting analytics totals:", err)
+		return nil, errors.New("Failed to get analytics")
+	}
+
+	response := &dto.AnalyticsOverviewResponse{
+		Range:    toAnalyticsRange(query),
+		Current:  toAnalyticsTotals(current),
+		Previous: toAnalyticsTotals(previous),
+	}
+	if previous.Views > 0 {
+		change := float64(current.Views-previous.Views) / float64(previous.Views) * 100
+		response.ViewsChange = &change
+	}
+	return response, nil
+}
+
+// **Analytics Trend**
+// Returns one point per bucket of the range, zero where nothing happened.
+func (s *analyticsService) GetTrend(req dto.AnalyticsDashboardRequest, userID uuid.UUID, role model.UserRole) (*dto.AnalyticsTrendResponse, error) {
+	query, err := s.reportQuery(req, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := s.reportRepo.Trend(query)
+	if err != nil {
+		log.Println("Error getting analytics trend:", err)
+		return nil, errors.New("Failed to get analytics")
+	}
+
+	byStart := make(map[int64]repository.AnalyticsTotals, len(buckets))
+	for _, bucket := range buckets {
+		byStart[bucket.BucketStart.Unix()] = bucket.AnalyticsTotals
+	}
+
+	points := []dto.AnalyticsTrendPoint{}
+	for start := query.From; start.Before(query.To); start = nextAnalyticsBucket(start, query.Granularity) {
+		totals := byStart[start.Unix()]
+		points = append(points, dto.AnalyticsTrendPoint{
+			Start:           start,
+			Views:           totals.Views,
+			UniqueVisitors:  totals.UniqueVisitors,
+			AverageReadTime: averageReadTime(totals),
+		})
+	}
+
+	return &dto.AnalyticsTrendResponse{
+		Range:  toAnalyticsRange(query),
+		Points: points,
+	}, nil
+}
+
+// **Top Articles**
+// Each article comes with its views per traffic channel.
+func (s *analyticsService) GetTopArticles(req dto.AnalyticsDashboardRequest, userID uuid.UUID, role model.UserRole) (*dto.AnalyticsTopArticlesResponse, error) {
+	query, err := s.reportQuery(req, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	_, limit := normalizePage(1, req.Limit)
+
+	stats, err := s.reportRepo.TopArticles(query, req.Sort, limit)
+	if err != nil {
+		log.Println("Error getting top articles:", err)
+		return nil, errors.New("Failed to get analytics")
+	}
+
+	articleIDs := make([]uuid.UUID, 0, len(stats))
+	for _, stat := range stats {
+		articleIDs = append(articleIDs, stat.ArticleID)
+	}
+
+	channels, err := s.reportRepo.ChannelsByArticle(query, articleIDs)
+	if err != nil {
+		log.Println("Error getting article traffic channels:", err)
+		return nil, errors.New("Failed to get analytics")
+	}
+
+	channelsByArticle := make(map[uuid.UUID][]dto.AnalyticsChannelCount)
+	for _, channel := range channels {
+		channelsByArticle[channel.ArticleID] = append(channelsByArticle[channel.ArticleID], toAnalyticsChannel(channel))
+	}
+
+	articles := make([]dto.AnalyticsTopArticle, 0, len(stats))
+	for _, stat := range stats {
+		articleChannels := channelsByArticle[stat.ArticleID]
+		if articleChannels == nil {
+			articleChannels = []dto.AnalyticsChannelCount{}
+		}
+
+		articles = append(articles, dto.AnalyticsTopArticle{
+			ArticleID:       stat.ArticleID,
+			Title:           stat.Title,
+			Slug:            stat.Slug,
+			URL:             ArticleURL(s.cfg, stat.Slug),
+			Views:           stat.Views,
+			UniqueVisitors:  stat.UniqueVisitors,
+			AverageReadTime: stat.AverageTimeSpent,
+			Channels:        articleChannels,
+		})
+	}
+
+	return &dto.AnalyticsTopArticlesResponse{
+		Range:    toAnalyticsRange(query),
+		Articles: articles,
+	}, nil
+}
+
+// **Traffic Sources**
+// Groups views by channel, by the source within each channel, by referrer
+// domain and by each UTM parameter.
+func (s *analyticsService) GetTrafficSources(req dto.AnalyticsDashboardRequest, userID uuid.UUID, role model.UserRole) (*dto.AnalyticsSourcesResponse, error) {
+	query, err := s.reportQuery(req, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	_, limit := normalizePage(1, req.Limit)
+
+	channels, err := s.reportRepo.Channels(query)
+	if err != nil {
+		log.Println("Error getting traffic channels:", err)
+		return nil, errors.New("Failed to get analytics")
+	}
+
+	sources, err := s.reportRepo.TopChannelSources(query, limit)
+	if err != nil {
+		log.Println("Error getting traffic channels:", err)
+		return nil, errors.New("Failed to get analytics")
+	}
+
+	response := &dto.AnalyticsSourcesResponse{
+		Range:    toAnalyticsRange(query),
+		Channels: make([]dto.AnalyticsChannelCount, 0, len(channels)),
+		Sources:  make([]dto.AnalyticsChannelSource, 0, len(sources)),
+	}
+	for _, channel := range channels {
+		response.Channels = append(response.Channels, toAnalyticsChannel(channel))
+	}
+	for _, source := range sources {
+		response.Sources = append(response.Sources, dto.AnalyticsChannelSource{
+			Channel: string(source.SourceType),
+			Name:    source.SourceName,
+			Views:   source.Views,
+		})
+	}
+
+	dimensions := []struct {
+		name   string
+		target *[]dto.AnalyticsSourceCount
+	}{
+		{"referrer_domain", &response.Referrers},
+		{"utm_source", &response.UTMSources},
+		{"utm_medium", &response.UTMMediums},
+		{"utm_campaign", &response.UTMCampaigns},
+	}
+
+	for _, dimension := range dimensions {
+		counts, err := s.reportRepo.TopSources(query, dimension.name, limit)
+		if err != nil {
+			log.Println("Error getting traffic sources:", err)
+			return nil, errors.New("Failed to get analytics")
+		}
+
+		*dimension.target = make([]dto.AnalyticsSourceCount, 0, len(counts))
+		for _, count := range counts {
+			name := count.Name
+			if name == "" {
+				name = constant.AnalyticsDirectReferrer
+			}
+			*dimension.target = append(*dimension.target, dto.AnalyticsSourceCount{Name: name, Views: count.Views})
+		}
+	}
+	return response, nil
+}
+
+// reportQuery resolves who and what a dashboard request covers. Authors
+// only see their own articles; users who may view any analytics see the
+// whole site unless they narrow it down.
+func (s *analyticsService) reportQuery(req dto.AnalyticsDashboardRequest, userID uuid.UUID, role model.UserRole) (repository.AnalyticsReportQuery, error) {
+	var query repository.AnalyticsReportQuery
+	viewAny := constant.HasPermission(role, constant.PermAnalyticsViewAny)
+
+	query.AuthorID = parseOptionalID(req.AuthorID)
+	switch {
+	case query.AuthorID == nil && !viewAny:
+		query.AuthorID = &userID
+	case query.AuthorID != nil && *query.AuthorID != userID && !viewAny:
+		return query, model.ErrAnalyticsForbidden
+	}
+
+	if query.ArticleID = parseOptionalID(req.ArticleID); query.ArticleID != nil {
+		article, err := s.articleRepo.GetArticleByID(*query.ArticleID)
+		if err != nil {
+			log.Println("Error getting article:", err)
+			return query, errors.New("Failed to get analytics")
+		}
+		if article == nil {
+			return query, model.ErrArticleNotFound
+		}
+		if article.AuthorID != userID && !viewAny {
+			return query, model.ErrAnalyticsForbidden
+		}
+	}
+
+	var err error
+	query.Granularity, query.From, query.To, err = resolveAnalyticsRange(req, time.Now().UTC())
+	return query, err
+}
+
+// resolveAnalyticsRange turns the range of a request into whole buckets of
+// its interval. Without a range or dates, the last 30 days are reported.
+func resolveAnalyticsRange(req dto.AnalyticsDashboardRequest, now time.Time) (model.AnalyticsGranularity, time.Time, time.Time, error) {
+	var from, to time.Time
+	var interval model.AnalyticsGranularity
+
+	if req.From != "" || req.To != "" {
+		start, end, err := parseDateRange(req.From, req.To)
+		if err != nil {
+			return "", from, to, err
+		}
+		if start == nil {
+			return "", from, to, model.ErrAnalyticsFromRequired
+		}
+
+		from, to = start.UTC(), now
+		if end != nil {
+			to = end.UTC()
+		}
+		interval = model.AnalyticsDaily
+		if to.Sub(from) <= 48*time.Hour {
+			interval = model.AnalyticsHourly
+		}
+	} else {
+		preset, ok := analyticsPresets[req.Range]
+		if !ok {
+			preset = analyticsPresets["30d"]
+		}
+
+		to = now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+		if preset.interval == model.AnalyticsHourly {
+			to = now.Truncate(time.Hour).Add(time.Hour)
+		}
+		from, interval = to.Add(-preset.length), preset.interval
+	}
+
+	if req.Interval != "" {
+		interval = model.AnalyticsGranularity(req.Interval)
+	}
+
+	maxDays := constant.AnalyticsMaxDailyRangeDays
+	bucket := 24 * time.Hour
+	if interval == model.AnalyticsHourly {
+		maxDays, bucket = constant.AnalyticsMaxHourlyRangeDays, time.Hour
+	}
+
+	from = from.Truncate(bucket)
+	if aligned := to.Truncate(bucket); aligned.Before(to) {
+		to = aligned.Add(bucket)
+	}
+	if to.Sub(from) > time.Duration(maxDays)*24*time.Hour {
+		return "", from, to, model.ErrAnalyticsRangeTooLong
+	}
+	return interval, from, to, nil
+}
+
+func nextAnalyticsBucket(start time.Time, granularity model.AnalyticsGranularity) time.Time {
+	if granularity == model.AnalyticsHourly {
+		return start.Add(time.Hour)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+func averageReadTime(totals repository.AnalyticsTotals) int64 {
+	if totals.Views == 0 {
+		return 0
+	}
+	return totals.TotalTimeSpent / totals.Views
+}
+
+func toAnalyticsRange(query repository.AnalyticsReportQuery) dto.AnalyticsRangeResponse {
+	return dto.AnalyticsRangeResponse{
+		From:     query.From,
+		To:       query.To,
+		Interval: string(query.Granularity),
+	}
+}
+
+func toAnalyticsTotals(totals repository.AnalyticsTotals) dto.AnalyticsTotalsResponse {
+	return dto.AnalyticsTotalsResponse{
+		Views:           totals.Views,
+		UniqueVisitors:  totals.UniqueVisitors,
+		TotalReadTime:   totals.TotalTimeSpent,
+		AverageReadTime: averageReadTime(totals),
+	}
+}
+
+func toAnalyticsChannel(count repository.AnalyticsChannelCount) dto.AnalyticsChannelCount {
+	return dto.AnalyticsChannelCount{
+		Channel: string(count.SourceType),
+		Views:   count.Views,
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+)
+
+// AnalyticsRollup periodically rolls raw analytics events up into hourly
+// and daily buckets per article, then applies the retention policy: raw
+// events and hourly buckets are deleted once they are older than their
+// retention, daily buckets are kept.
+type AnalyticsRollup interface {
+	Start(ctx context.Context)
+	RunOnce()
+}
+
+type analyticsRollup struct {
+	repo            repository.AnalyticsRepository
+	interval        time.Duration
+	eventRetention  time.Duration
+	hourlyRetention time.Duration
+}
+
+func NewAnalyticsRollup(repo repository.AnalyticsRepository, interval, eventRetention, hourlyRetention time.Duration) AnalyticsRollup {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	return &analyticsRollup{
+		repo:            repo,
+		interval:        interval,
+		eventRetention:  eventRetention,
+		hourlyRetention: hourlyRetention,
+	}
+}
+
+// **Start Rollup**
+// Runs in the background until ctx is cancelled.
+func (r *analyticsRollup) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		r.RunOnce()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.RunOnce()
+			}
+		}
+	}()
+}
+
+// **Roll Up Events**
+// Recomputes from the start of the day of the latest bucket, less the lag,
+// so hits that were written late still land in their bucket. Raw events
+// that may not have been rolled up yet are never purged.
+func (r *analyticsRollup) RunOnce() {
+	last, err := r.repo.LastRollupBucket()
+	if err != nil {
+		log.Println("Error reading analytics rollup progress:", err)
+		return
+	}
+
+	var since time.Time
+	if last != nil {
+		since = last.UTC().Add(-constant.AnalyticsRollupLagMinutes * time.Minute).Truncate(24 * time.Hour)
+	}
+
+	if err := r.repo.RollupEvents(since); err != nil {
+		log.Println("Error rolling up analytics events:", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	eventCutoff := now.Add(-r.eventRetention)
+	if since.Before(eventCutoff) {
+		eventCutoff = since
+	}
+
+	purged, err := r.repo.PurgeEvents(eventCutoff)
+	if err != nil {
+		log.Println("Error purging analytics events:", err)
+	} else if purged > 0 {
+		log.Printf("Purged %d analytics events older than %s", purged, eventCutoff.Format(time.RFC3339))
+	}
+
+	purged, err = r.repo.PurgeHourlyRollups(now.Add(-r.hourlyRetention))
+	if err != nil {
+		log.Println("Error purging hourly analytics rollups:", err)
+	} else if purged > 0 {
+		log.Printf("Purged %d hourly analytics rollups", purged)
+	}
+}
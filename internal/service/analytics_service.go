@@ -1 +1,305 @@
 package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+)
+
+// botUserAgent matches crawlers, link previewers, monitors and HTTP
+// libraries. Real browsers never send an empty user agent either.
+var botUserAgent = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|scrap|fetch|preview|monitor|uptime|lighthouse|pagespeed|headless|phantom|facebookexternalhit|embedly|whatsapp|telegram|curl|wget|python|java/|go-http-client|okhttp|axios|node-fetch|libwww|httpclient`)
+
+// BeaconInput is one hit from the analytics beacon, with the client
+// details the handler reads from the request.
+type BeaconInput struct {
+	Type      model.AnalyticsEventType
+	ArticleID uuid.UUID
+	Seconds   int
+	Referrer  string
+	PageURL   string
+	IPAddress string
+	UserAgent string
+}
+
+type AnalyticsService interface {
+	RecordBeacon(input BeaconInput) error
+	GetOverview(req dto.AnalyticsDashboardRequest, userID uuid.UUID, role model.UserRole) (*dto.AnalyticsOverviewResponse, error)
+	GetTrend(req dto.AnalyticsDashboardRequest, userID uuid.UUID, role model.UserRole) (*dto.AnalyticsTrendResponse, error)
+	GetTopArticles(req dto.AnalyticsDashboardRequest, userID uuid.UUID, role model.UserRole) (*dto.AnalyticsTopArticlesResponse, error)
+	GetTrafficSources(req dto.AnalyticsDashboardRequest, userID uuid.UUID, role model.UserRole) (*dto.AnalyticsSourcesResponse, error)
+}
+
+type analyticsService struct {
+	repo        repository.AnalyticsRepository
+	reportRepo  repository.AnalyticsReportRepository
+	articleRepo repository.ArticleRepository
+	collector   AnalyticsCollector
+	classifier  TrafficClassifier
+	cfg         *config.Config
+
+	saltMu  sync.Mutex
+	saltDay time.Time
+	salt    []byte
+
+	visitMu   sync.Mutex
+	lastSeen  map[string]time.Time
+	lastPrune time.Time
+
+	rateMu     sync.Mutex
+	rateStart  time.Time
+	rateCounts map[string]int
+
+	articleMu      sync.Mutex
+	publishedIDs   map[uuid.UUID]bool
+	articlesLoaded time.Time
+}
+
+func NewAnalyticsService(repo repository.AnalyticsRepository, reportRepo repository.AnalyticsReportRepository, articleRepo repository.ArticleRepository, collector AnalyticsCollector, classifier TrafficClassifier, cfg *config.Config) AnalyticsService {
+	return &analyticsService{
+		repo:        repo,
+		reportRepo:  reportRepo,
+		articleRepo: articleRepo,
+		collector:   collector,
+		classifier:  classifier,
+		cfg:         cfg,
+		lastSeen:    make(map[string]time.Time),
+		lastPrune:   time.Now(),
+		rateCounts:  make(map[string]int),
+	}
+}
+
+// **Record Beacon**
+// Queues a page view or a dwell-time heartbeat. Bots and hits on articles
+// that are not published are ignored without an error. A heartbeat is
+// credited with no more than the time since the visitor's previous hit on
+// the article, so a client cannot inflate the reading time by claiming long
+// intervals or sending heartbeats in a burst.
+func (s *analyticsService) RecordBeacon(input BeaconInput) error {
+	now := time.Now().UTC()
+	if !s.allowHit(input.IPAddress, now) {
+		return model.ErrAnalyticsRateLimited
+	}
+
+	if isBotUserAgent(input.UserAgent) || !s.isPublished(input.ArticleID, now) {
+		return nil
+	}
+
+	visitorHash, err := s.visitorHash(now, input.IPAddress, input.UserAgent)
+	if err != nil {
+		log.Println("Error loading analytics salt:", err)
+		return errors.New("Failed to record analytics")
+	}
+
+	event := model.AnalyticsEvent{
+		ArticleID:   input.ArticleID,
+		Type:        input.Type,
+		VisitorHash: visitorHash,
+		CreatedAt:   now,
+	}
+
+	elapsed, seen := s.touchVisit(visitorHash+input.ArticleID.String(), now)
+	switch input.Type {
+	case model.AnalyticsView:
+		event.Referrer = cleanReferrer(input.Referrer)
+		event.ReferrerDomain = referrerDomain(event.Referrer)
+		event.UTMSource, event.UTMMedium, event.UTMCampaign = utmParams(input.PageURL)
+		event.SourceType, event.SourceName = s.classifier.Classify(event.ReferrerDomain, event.UTMSource, event.UTMMedium)
+	case model.AnalyticsHeartbeat:
+		if !seen {
+			return nil
+		}
+		event.Seconds = min(input.Seconds, int(elapsed/time.Second), constant.AnalyticsMaxHeartbeatSeconds)
+		if event.Seconds <= 0 {
+			return nil
+		}
+	}
+
+	s.collector.Record(event)
+	return nil
+}
+
+// visitorHash identifies a visitor for the day without storing anything
+// that points back to them: the IP address and user agent are hashed with
+// a salt that is thrown away once the day is over.
+func (s *analyticsService) visitorHash(now time.Time, ip, userAgent string) (string, error) {
+	salt, err := s.dailySalt(now.Truncate(24 * time.Hour))
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(salt)
+	hash.Write([]byte(ip))
+	hash.Write([]byte{0})
+	hash.Write([]byte(userAgent))
+	return hex.EncodeToString(hash.Sum(nil)[:16]), nil
+}
+
+func (s *analyticsService) dailySalt(day time.Time) ([]byte, error) {
+	s.saltMu.Lock()
+	defer s.saltMu.Unlock()
+
+	if s.salt != nil && s.saltDay.Equal(day) {
+		return s.salt, nil
+	}
+
+	candidate := make([]byte, 32)
+	if _, err := rand.Read(candidate); err != nil {
+		return nil, err
+	}
+
+	salt, err := s.repo.EnsureDailySalt(day, candidate)
+	if err != nil {
+		return nil, err
+	}
+
+	s.saltDay, s.salt = day, salt
+	return salt, nil
+}
+
+// allowHit counts a hit against the IP's budget for the current window.
+// Every IP starts over when a new window begins.
+func (s *analyticsService) allowHit(ip string, now time.Time) bool {
+	if s.cfg.AnalyticsBeaconRateLimit <= 0 {
+		return true
+	}
+
+	s.rateMu.Lock()
+	defer s.rateMu.Unlock()
+
+	if now.Sub(s.rateStart) >= s.cfg.AnalyticsBeaconRateWindow {
+		s.rateStart = now
+		clear(s.rateCounts)
+	}
+
+	count, ok := s.rateCounts[ip]
+	if !ok && len(s.rateCounts) >= constant.AnalyticsMaxTrackedKeys {
+		return true
+	}
+	if count >= s.cfg.AnalyticsBeaconRateLimit {
+		return false
+	}
+
+	s.rateCounts[ip] = count + 1
+	return true
+}
+
+// isPublished checks the article against a cached set of published IDs,
+// reloaded when it is missing and the set is old enough, so hits on random
+// IDs cost no query each. When the IDs cannot be loaded the hit is kept,
+// since RecordEvents drops hits on unknown articles anyway.
+func (s *analyticsService) isPublished(articleID uuid.UUID, now time.Time) bool {
+	s.articleMu.Lock()
+	defer s.articleMu.Unlock()
+
+	if s.publishedIDs[articleID] {
+		return true
+	}
+	if now.Sub(s.articlesLoaded) < constant.AnalyticsArticleRefreshSeconds*time.Second {
+		return false
+	}
+
+	ids, err := s.repo.ListPublishedArticleIDs()
+	if err != nil {
+		log.Println("Error loading published articles:", err)
+		return true
+	}
+
+	s.publishedIDs = make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		s.publishedIDs[id] = true
+	}
+	s.articlesLoaded = now
+	return s.publishedIDs[articleID]
+}
+
+// touchVisit records a hit of a visitor on an article and returns the time
+// since their previous one, if that was recent enough to be the same visit.
+// Once the map is full, new visits are not tracked and their heartbeats go
+// uncredited.
+func (s *analyticsService) touchVisit(key string, now time.Time) (time.Duration, bool) {
+	timeout := constant.AnalyticsVisitTimeoutMinutes * time.Minute
+
+	s.visitMu.Lock()
+	defer s.visitMu.Unlock()
+
+	if now.Sub(s.lastPrune) > timeout {
+		for k, seen := range s.lastSeen {
+			if now.Sub(seen) > timeout {
+				delete(s.lastSeen, k)
+			}
+		}
+		s.lastPrune = now
+	}
+
+	previous, ok := s.lastSeen[key]
+	if !ok && len(s.lastSeen) >= constant.AnalyticsMaxTrackedKeys {
+		return 0, false
+	}
+	s.lastSeen[key] = now
+	if !ok || now.Sub(previous) > timeout {
+		return 0, false
+	}
+	return now.Sub(previous), true
+}
+
+func isBotUserAgent(userAgent string) bool {
+	return strings.TrimSpace(userAgent) == "" || botUserAgent.MatchString(userAgent)
+}
+
+// cleanReferrer keeps the origin and path of the referring page. Query
+// strings and fragments are dropped, since they may carry personal data.
+func cleanReferrer(referrer string) string {
+	u, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return truncateRunes(u.Scheme+"://"+strings.ToLower(u.Host)+u.EscapedPath(), 2048)
+}
+
+// referrerDomain groups referrers by host, ignoring the port and a
+// leading "www.".
+func referrerDomain(referrer string) string {
+	u, err := url.Parse(referrer)
+	if err != nil {
+		return ""
+	}
+	return truncateRunes(strings.TrimPrefix(u.Hostname(), "www."), 255)
+}
+
+func utmParams(pageURL string) (string, string, string) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return "", "", ""
+	}
+
+	query := u.Query()
+	value := func(name string) string {
+		return truncateRunes(strings.ToLower(strings.TrimSpace(query.Get(name))), 255)
+	}
+	return value("utm_source"), value("utm_medium"), value("utm_campaign")
+}
+
+// truncateRunes cuts value to the length of the column it is stored in.
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
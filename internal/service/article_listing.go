@@ -0,0 +1,193 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+)
+
+// articleListing is what a listing endpoint fixes on top of the client's
+// request: the sort used when none is asked for and the filters the
+// client cannot change.
+type articleListing struct {
+	defaultSort string
+	statuses    []model.ArticleStatus
+	authorID    *uuid.UUID
+}
+
+// articleCursor is the decoded form of the opaque cursor handed to clients.
+// It records the order it was issued for, so it cannot be replayed against
+// a different one.
+type articleCursor struct {
+	Sort  string    `json:"s"`
+	Order string    `json:"o"`
+	Value *string   `json:"v,omitempty"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// listArticles returns one page of articles and the cursor of the next
+// page, empty on the last page.
+func listArticles(repo repository.ArticleRepository, req dto.ArticleListRequest, listing articleListing) ([]model.Article, string, error) {
+	sort := req.Sort
+	if sort == "" {
+		sort = listing.defaultSort
+	}
+	order := req.Order
+	if order == "" {
+		order = constant.OrderDesc
+		if sort == constant.SortTitle {
+			order = constant.OrderAsc
+		}
+	}
+	_, limit := normalizePage(1, req.Limit)
+
+	params := repository.ArticleListParams{
+		Statuses:   listing.statuses,
+		AuthorID:   parseOptionalID(req.AuthorID),
+		CategoryID: parseOptionalID(req.CategoryID),
+		TagID:      parseOptionalID(req.TagID),
+		DateColumn: sort,
+		SortColumn: sort,
+		Descending: order == constant.OrderDesc,
+		Limit:      limit + 1,
+	}
+	if listing.authorID != nil {
+		params.AuthorID = listing.authorID
+	}
+	if sort == constant.SortTitle {
+		params.DateColumn = constant.SortPublishedAt
+	}
+
+	var err error
+	if params.From, params.To, err = parseDateRange(req.From, req.To); err != nil {
+		return nil, "", err
+	}
+
+	if req.Cursor != "" {
+		if params.After, err = decodeArticleCursor(req.Cursor, sort, order); err != nil {
+			return nil, "", err
+		}
+	}
+
+	articles, err := repo.ListArticles(params)
+	if err != nil {
+		log.Println("Error listing articles:", err)
+		return nil, "", errors.New("Failed to get articles")
+	}
+
+	if len(articles) <= limit {
+		return articles, "", nil
+	}
+
+	articles = articles[:limit]
+	return articles, encodeArticleCursor(&articles[limit-1], sort, order), nil
+}
+
+func encodeArticleCursor(article *model.Article, sort, order string) string {
+	cursor := articleCursor{Sort: sort, Order: order, ID: article.ID}
+
+	var value string
+	switch sort {
+	case constant.SortPublishedAt:
+		if article.PublishedAt != nil {
+			value = article.PublishedAt.UTC().Format(time.RFC3339Nano)
+			cursor.Value = &value
+		}
+	case constant.SortCreatedAt:
+		value = article.CreatedAt.UTC().Format(time.RFC3339Nano)
+		cursor.Value = &value
+	case constant.SortUpdatedAt:
+		value = article.UpdatedAt.UTC().Format(time.RFC3339Nano)
+		cursor.Value = &value
+	case constant.SortTitle:
+		cursor.Value = &article.Title
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeArticleCursor(encoded, sort, order string) (*repository.ArticleKey, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, model.ErrInvalidCursor
+	}
+
+	var cursor articleCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != sort || cursor.Order != order {
+		return nil, model.ErrInvalidCursor
+	}
+
+	key := &repository.ArticleKey{ID: cursor.ID}
+	switch {
+	case cursor.Value == nil:
+		if sort != constant.SortPublishedAt {
+			return nil, model.ErrInvalidCursor
+		}
+	case sort == constant.SortTitle:
+		key.Value = *cursor.Value
+	default:
+		t, err := time.Parse(time.RFC3339Nano, *cursor.Value)
+		if err != nil {
+			return nil, model.ErrInvalidCursor
+		}
+		key.Value = t
+	}
+	return key, nil
+}
+
+// parseDateRange reads the from and to filters of a listing. A bare date
+// as the end of the range moves to the start of the next day, since the
+// end is exclusive.
+func parseDateRange(from, to string) (*time.Time, *time.Time, error) {
+	start, err := parseDateFilter(from, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	end, err := parseDateFilter(to, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if start != nil && end != nil && !start.Before(*end) {
+		return nil, nil, model.ErrDateFilterRange
+	}
+	return start, end, nil
+}
+
+func parseDateFilter(value string, end bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, model.ErrDateFilterInvalid
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// parseOptionalID parses an ID that has already been validated; an empty
+// value means no filter.
+func parseOptionalID(value string) *uuid.UUID {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
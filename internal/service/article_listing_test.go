@@ -0,0 +1,141 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+)
+
+func TestArticleCursorRoundTrip(t *testing.T) {
+	published := time.Date(2024, 3, 1, 10, 30, 0, 123456789, time.FixedZone("WIB", 7*3600))
+	article := &model.Article{
+		ID:          uuid.New(),
+		Title:       "Keyset pagination, explained",
+		PublishedAt: &published,
+	}
+	article.CreatedAt = published.Add(-time.Hour)
+	article.UpdatedAt = published.Add(time.Hour)
+	unpublished := &model.Article{ID: uuid.New()}
+
+	tests := []struct {
+		name    string
+		article *model.Article
+		sort    string
+		want    interface{}
+	}{
+		{"published at", article, constant.SortPublishedAt, published},
+		{"unpublished sorts as NULL", unpublished, constant.SortPublishedAt, nil},
+		{"created at", article, constant.SortCreatedAt, article.CreatedAt},
+		{"updated at", article, constant.SortUpdatedAt, article.UpdatedAt},
+		{"title", article, constant.SortTitle, article.Title},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, order := range []string{constant.OrderAsc, constant.OrderDesc} {
+				key, err := decodeArticleCursor(encodeArticleCursor(tt.article, tt.sort, order), tt.sort, order)
+				if err != nil {
+					t.Fatalf("decodeArticleCursor() = %v", err)
+				}
+				if key.ID != tt.article.ID {
+					t.Errorf("ID = %v, want %v", key.ID, tt.article.ID)
+				}
+
+				switch want := tt.want.(type) {
+				case time.Time:
+					if got, ok := key.Value.(time.Time); !ok || !got.Equal(want) {
+						t.Errorf("Value = %v, want %v", key.Value, want)
+					}
+				default:
+					if key.Value != tt.want {
+						t.Errorf("Value = %v, want %v", key.Value, tt.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeArticleCursorRejectsInvalid(t *testing.T) {
+	article := &model.Article{ID: uuid.New(), Title: "Title"}
+	article.CreatedAt = time.Now()
+	valid := encodeArticleCursor(article, constant.SortCreatedAt, constant.OrderDesc)
+	raw := func(json string) string { return base64.RawURLEncoding.EncodeToString([]byte(json)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+		sort   string
+		order  string
+	}{
+		{"other sort", valid, constant.SortUpdatedAt, constant.OrderDesc},
+		{"other order", valid, constant.SortCreatedAt, constant.OrderAsc},
+		{"not base64", "not a cursor!", constant.SortCreatedAt, constant.OrderDesc},
+		{"truncated", valid[:len(valid)-4], constant.SortCreatedAt, constant.OrderDesc},
+		{"not json", raw("created_at"), constant.SortCreatedAt, constant.OrderDesc},
+		{"bad time", raw(`{"s":"created_at","o":"desc","v":"yesterday","id":"` + article.ID.String() + `"}`), constant.SortCreatedAt, constant.OrderDesc},
+		{"bad id", raw(`{"s":"created_at","o":"desc","v":"2024-03-01T00:00:00Z","id":"1"}`), constant.SortCreatedAt, constant.OrderDesc},
+		{"NULL on a column without NULLs", raw(`{"s":"title","o":"asc","id":"` + article.ID.String() + `"}`), constant.SortTitle, constant.OrderAsc},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeArticleCursor(tt.cursor, tt.sort, tt.order); !errors.Is(err, model.ErrInvalidCursor) {
+				t.Errorf("decodeArticleCursor() = %v, want %v", err, model.ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	date := func(value string) *time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &parsed
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		wantFrom *time.Time
+		wantTo   *time.Time
+		wantErr  error
+	}{
+		{name: "no range"},
+		{name: "open end", from: "2024-03-01", wantFrom: date("2024-03-01T00:00:00Z")},
+		{name: "bare end date includes the whole day", to: "2024-03-31", wantTo: date("2024-04-01T00:00:00Z")},
+		{name: "single day", from: "2024-03-01", to: "2024-03-01", wantFrom: date("2024-03-01T00:00:00Z"), wantTo: date("2024-03-02T00:00:00Z")},
+		{name: "timestamps are kept as given", from: "2024-03-01T08:00:00+07:00", to: "2024-03-01T09:00:00Z",
+			wantFrom: date("2024-03-01T01:00:00Z"), wantTo: date("2024-03-01T09:00:00Z")},
+		{name: "invalid from", from: "01/03/2024", wantErr: model.ErrDateFilterInvalid},
+		{name: "invalid to", to: "2024-02-30", wantErr: model.ErrDateFilterInvalid},
+		{name: "start after end", from: "2024-03-02", to: "2024-03-01", wantErr: model.ErrDateFilterRange},
+		{name: "empty timestamp range", from: "2024-03-01T09:00:00Z", to: "2024-03-01T09:00:00Z", wantErr: model.ErrDateFilterRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := parseDateRange(tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !sameTime(from, tt.wantFrom) || !sameTime(to, tt.wantTo) {
+				t.Errorf("range = %v - %v, want %v - %v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+)
+
+// ArticleScheduler periodically publishes articles whose scheduled time has
+// passed. Pending schedules live in the articles table, so nothing is lost
+// when the server restarts.
+type ArticleScheduler interface {
+	Start(ctx context.Context)
+	RunOnce()
+}
+
+type articleScheduler struct {
+	workflowRepo repository.ArticleWorkflowRepository
+	interval     time.Duration
+}
+
+func NewArticleScheduler(workflowRepo repository.ArticleWorkflowRepository, interval time.Duration) ArticleScheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &articleScheduler{
+		workflowRepo: workflowRepo,
+		interval:     interval,
+	}
+}
+
+// **Start Scheduler**
+// Runs in the background until ctx is cancelled.
+func (s *articleScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.RunOnce()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RunOnce()
+			}
+		}
+	}()
+}
+
+// **Publish Due Articles**
+func (s *articleScheduler) RunOnce() {
+	articles, err := s.workflowRepo.PublishDueArticles(time.Now())
+	if err != nil {
+		log.Println("Error publishing scheduled articles:", err)
+		return
+	}
+
+	for _, article := range articles {
+		log.Printf("Published scheduled article %s (%s)", article.ID, article.Slug)
+	}
+}
@@ -1 +1,410 @@
 package service
+
+import (
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type ArticleService interface {
+	CreateArticle(article *model.Article) (*model.Article, error)
+	GetArticleByID(id, requesterID uuid.UUID, role model.UserRole) (*model.Article, error)
+	GetArticleBySlug(slug string, requesterID uuid.UUID, role model.UserRole) (*model.Article, string, error)
+	ListArticles(req dto.ArticleListRequest, role model.UserRole) ([]model.Article, string, error)
+	ListAuthorArticles(authorID uuid.UUID, req dto.ArticleListRequest) ([]model.Article, string, error)
+	UpdateArticle(id, userID uuid.UUID, role model.UserRole, req dto.UpdateArticleRequest) (*model.Article, error)
+	DeleteArticle(id, userID uuid.UUID, role model.UserRole) error
+
+	ListArticleVersions(id, userID uuid.UUID, role model.UserRole) ([]model.ArticleVersion, error)
+	DiffArticleVersions(id, userID uuid.UUID, role model.UserRole, fromID, toID uuid.UUID) (*dto.ArticleDiffResponse, error)
+	RestoreArticleVersion(id, userID uuid.UUID, role model.UserRole, versionID uuid.UUID) (*model.Article, error)
+}
+
+// slugAttempts is how many times a generated slug is picked again after a
+// concurrent write took it.
+const slugAttempts = 3
+
+type articleService struct {
+	repo         repository.ArticleRepository
+	versionRepo  repository.ArticleVersionRepository
+	redirectRepo repository.SlugRedirectRepository
+}
+
+func NewArticleService(repo repository.ArticleRepository, versionRepo repository.ArticleVersionRepository, redirectRepo repository.SlugRedirectRepository) ArticleService {
+	return &articleService{
+		repo:         repo,
+		versionRepo:  versionRepo,
+		redirectRepo: redirectRepo,
+	}
+}
+
+// **Create Article**
+// A slug supplied by the caller must be free; otherwise one is generated
+// from the title and suffixed with -2, -3, ... on collision.
+func (s *articleService) CreateArticle(article *model.Article) (*model.Article, error) {
+	slug := utils.Slugify(article.Slug)
+	requested := slug != ""
+	if requested {
+		if err := s.ensureSlugAvailable(slug, uuid.Nil); err != nil {
+			return nil, err
+		}
+	} else {
+		generated, err := s.generateSlug(article.Title, "")
+		if err != nil {
+			return nil, err
+		}
+		slug = generated
+	}
+	article.Slug = slug
+
+	article.Status = model.Draft
+	article.PublishedAt = nil
+
+	newArticle, err := s.repo.CreateArticle(article)
+	for attempt := 1; errors.Is(err, model.ErrSlugAlreadyExists) && !requested && attempt < slugAttempts; attempt++ {
+		if article.Slug, err = s.generateSlug(article.Title, ""); err != nil {
+			return nil, err
+		}
+		newArticle, err = s.repo.CreateArticle(article)
+	}
+	if errors.Is(err, model.ErrSlugAlreadyExists) {
+		return nil, err
+	}
+	if err != nil {
+		log.Println("Error creating article:", err)
+		return nil, errors.New("Failed to create article")
+	}
+
+	return s.repo.GetArticleByID(newArticle.ID)
+}
+
+// **Get Article By ID**
+// Unpublished articles are only visible to their author and to reviewers.
+func (s *articleService) GetArticleByID(id, requesterID uuid.UUID, role model.UserRole) (*model.Article, error) {
+	article, err := s.repo.GetArticleByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if article == nil {
+		return nil, model.ErrArticleNotFound
+	}
+
+	if !canViewArticle(article, requesterID, role) {
+		return nil, model.ErrArticleNotFound
+	}
+
+	return article, nil
+}
+
+// **Get Article By Slug**
+// When slug is an old slug of an article, the article's current slug is
+// returned as the redirect target instead of the article.
+func (s *articleService) GetArticleBySlug(slug string, requesterID uuid.UUID, role model.UserRole) (*model.Article, string, error) {
+	article, err := s.repo.GetArticleBySlug(slug)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if article != nil {
+		if !canViewArticle(article, requesterID, role) {
+			return nil, "", model.ErrArticleNotFound
+		}
+		return article, "", nil
+	}
+
+	redirect, err := s.redirectRepo.GetSlugRedirect(slug)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if redirect == nil || !canViewArticle(&redirect.Article, requesterID, role) {
+		return nil, "", model.ErrArticleNotFound
+	}
+
+	return nil, redirect.Article.Slug, nil
+}
+
+// **List Articles**
+// Lists published articles, newest first. Roles that review articles may
+// list other statuses too.
+func (s *articleService) ListArticles(req dto.ArticleListRequest, role model.UserRole) ([]model.Article, string, error) {
+	status := model.ArticleStatus(req.Status)
+	if status == "" {
+		status = model.Published
+	}
+	if status != model.Published && !constant.HasPermission(role, constant.PermArticleReview) {
+		return nil, "", model.ErrArticleListForbidden
+	}
+
+	return listArticles(s.repo, req, articleListing{
+		defaultSort: constant.SortPublishedAt,
+		statuses:    []model.ArticleStatus{status},
+	})
+}
+
+// **List Articles By Author**
+// Lists the author's own articles in any status, last edited first.
+func (s *articleService) ListAuthorArticles(authorID uuid.UUID, req dto.ArticleListRequest) ([]model.Article, string, error) {
+	listing := articleListing{
+		defaultSort: constant.SortUpdatedAt,
+		authorID:    &authorID,
+	}
+	if req.Status != "" {
+		listing.statuses = []model.ArticleStatus{model.ArticleStatus(req.Status)}
+	}
+
+	return listArticles(s.repo, req, listing)
+}
+
+// **Update Article**
+func (s *articleService) UpdateArticle(id, userID uuid.UUID, role model.UserRole, req dto.UpdateArticleRequest) (*model.Article, error) {
+	article, err := s.getOwnedArticle(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	titleChanged := req.Title != nil && *req.Title != article.Title
+
+	if req.Title != nil {
+		article.Title = *req.Title
+	}
+
+	if req.Content != nil {
+		article.Content = *req.Content
+	}
+
+	oldSlug := article.Slug
+	newSlug := oldSlug
+	requested := req.Slug != nil && utils.Slugify(*req.Slug) != ""
+	if requested {
+		newSlug = utils.Slugify(*req.Slug)
+		if newSlug != oldSlug {
+			if err := s.ensureSlugAvailable(newSlug, article.ID); err != nil {
+				return nil, err
+			}
+		}
+	} else if titleChanged || req.Slug != nil {
+		newSlug, err = s.generateSlug(article.Title, oldSlug)
+		if err != nil {
+			return nil, err
+		}
+	}
+	article.Slug = newSlug
+
+	err = s.repo.UpdateArticle(article)
+	for attempt := 1; errors.Is(err, model.ErrSlugAlreadyExists) && !requested && attempt < slugAttempts; attempt++ {
+		if newSlug, err = s.generateSlug(article.Title, oldSlug); err != nil {
+			return nil, err
+		}
+		article.Slug = newSlug
+		err = s.repo.UpdateArticle(article)
+	}
+	if errors.Is(err, model.ErrSlugAlreadyExists) {
+		return nil, err
+	}
+	if err != nil {
+		log.Println("Error updating article:", err)
+		return nil, errors.New("Failed to update article")
+	}
+
+	if newSlug != oldSlug {
+		s.recordSlugChange(article.ID, oldSlug, newSlug)
+	}
+
+	return article, nil
+}
+
+// **Delete Article**
+func (s *articleService) DeleteArticle(id, userID uuid.UUID, role model.UserRole) error {
+	article, err := s.getOwnedArticle(id, userID, role)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteArticle(article.ID)
+}
+
+// **List Article Versions**
+func (s *articleService) ListArticleVersions(id, userID uuid.UUID, role model.UserRole) ([]model.ArticleVersion, error) {
+	article, err := s.getOwnedArticle(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.versionRepo.ListArticleVersions(article.ID)
+}
+
+// **Diff Article Versions**
+// A nil fromID or toID refers to the current content of the article.
+func (s *articleService) DiffArticleVersions(id, userID uuid.UUID, role model.UserRole, fromID, toID uuid.UUID) (*dto.ArticleDiffResponse, error) {
+	article, err := s.getOwnedArticle(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	from, fromContent, err := s.diffSide(article, fromID)
+	if err != nil {
+		return nil, err
+	}
+
+	to, toContent, err := s.diffSide(article, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ArticleDiffResponse{
+		From:         from,
+		To:           to,
+		TitleChanged: from.Title != to.Title,
+		Lines:        utils.DiffLines(fromContent, toContent),
+	}, nil
+}
+
+// **Restore Article Version**
+// The current content is snapshotted first, so a restore is itself a new
+// revision and can be undone.
+func (s *articleService) RestoreArticleVersion(id, userID uuid.UUID, role model.UserRole, versionID uuid.UUID) (*model.Article, error) {
+	article, err := s.getOwnedArticle(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.getArticleVersion(article.ID, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	article.Title = version.Title
+	article.Content = version.Content
+
+	if err := s.repo.UpdateArticle(article); err != nil {
+		log.Println("Error restoring article version:", err)
+		return nil, errors.New("Failed to restore article version")
+	}
+
+	return article, nil
+}
+
+func (s *articleService) getArticleVersion(articleID, versionID uuid.UUID) (*model.ArticleVersion, error) {
+	version, err := s.versionRepo.GetArticleVersionByID(versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == nil || version.ArticleID != articleID {
+		return nil, model.ErrArticleVersionNotFound
+	}
+
+	return version, nil
+}
+
+func (s *articleService) diffSide(article *model.Article, versionID uuid.UUID) (dto.ArticleDiffSide, string, error) {
+	if versionID == uuid.Nil {
+		return dto.ArticleDiffSide{Title: article.Title}, article.Content, nil
+	}
+
+	version, err := s.getArticleVersion(article.ID, versionID)
+	if err != nil {
+		return dto.ArticleDiffSide{}, "", err
+	}
+
+	return dto.ArticleDiffSide{
+		ID:      &version.ID,
+		Version: version.Version,
+		Title:   version.Title,
+	}, version.Content, nil
+}
+
+func (s *articleService) getOwnedArticle(id, userID uuid.UUID, role model.UserRole) (*model.Article, error) {
+	return findOwnedArticle(s.repo, id, userID, role)
+}
+
+func (s *articleService) ensureSlugAvailable(slug string, articleID uuid.UUID) error {
+	existing, err := s.repo.GetArticleBySlug(slug)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.ID != articleID {
+		return model.ErrSlugAlreadyExists
+	}
+
+	return nil
+}
+
+// generateSlug builds a free slug from title. currentSlug is the slug the
+// article already owns (empty for new articles). It is kept only when it is
+// the base slug or the exact "-N" suffix the dedup would pick again, so a
+// title that lost a trailing number does not keep it in the slug.
+func (s *articleService) generateSlug(title, currentSlug string) (string, error) {
+	base := utils.Slugify(title)
+	if base == "" {
+		base = "article"
+	}
+
+	existing, err := s.repo.ListSlugsWithPrefix(base)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.UniqueSlug(base, currentSlug, existing), nil
+}
+
+// recordSlugChange keeps the old slug resolvable and frees the new slug from
+// any redirect that previously claimed it.
+func (s *articleService) recordSlugChange(articleID uuid.UUID, oldSlug, newSlug string) {
+	if err := s.redirectRepo.SaveSlugRedirect(oldSlug, articleID); err != nil {
+		log.Println("Error saving slug redirect:", err)
+	}
+
+	if err := s.redirectRepo.DeleteSlugRedirect(newSlug); err != nil {
+		log.Println("Error deleting slug redirect:", err)
+	}
+}
+
+func canViewArticle(article *model.Article, requesterID uuid.UUID, role model.UserRole) bool {
+	return article.Status == model.Published ||
+		article.AuthorID == requesterID ||
+		constant.HasPermission(role, constant.PermArticleReview)
+}
+
+// findVisibleArticle loads an article the requester may read; hidden
+// articles are reported as not found.
+func findVisibleArticle(repo repository.ArticleRepository, id, requesterID uuid.UUID, role model.UserRole) (*model.Article, error) {
+	article, err := repo.GetArticleByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if article == nil || !canViewArticle(article, requesterID, role) {
+		return nil, model.ErrArticleNotFound
+	}
+
+	return article, nil
+}
+
+// findOwnedArticle loads an article the user may modify: their own, or any
+// article for roles that can manage all articles.
+func findOwnedArticle(repo repository.ArticleRepository, id, userID uuid.UUID, role model.UserRole) (*model.Article, error) {
+	article, err := repo.GetArticleByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if article == nil {
+		return nil, model.ErrArticleNotFound
+	}
+
+	if article.AuthorID != userID && !constant.HasPermission(role, constant.PermArticleManageAny) {
+		return nil, model.ErrArticleForbidden
+	}
+
+	return article, nil
+}
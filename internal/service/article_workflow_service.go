@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+)
+
+type ArticleWorkflowService interface {
+	TransitionArticle(articleID, actorID uuid.UUID, role model.UserRole, to model.ArticleStatus, reason string, scheduledAt *time.Time) (*model.Article, error)
+	GetStatusHistory(articleID, userID uuid.UUID, role model.UserRole) ([]model.ArticleStatusHistory, error)
+	ListReviewQueue(req dto.ArticleListRequest) ([]model.Article, string, error)
+}
+
+// statusTransition describes who may move an article between two statuses.
+// When ownerOnly is set the actor must be the article author, regardless of role.
+type statusTransition struct {
+	permission       constant.Permission
+	ownerOnly        bool
+	requiresReason   bool
+	requiresSchedule bool
+}
+
+var articleTransitions = map[model.ArticleStatus]map[model.ArticleStatus]statusTransition{
+	model.Draft: {
+		model.Review: {ownerOnly: true},
+	},
+	model.Review: {
+		model.Published: {permission: constant.PermArticlePublish},
+		model.Scheduled: {permission: constant.PermArticlePublish, requiresSchedule: true},
+		model.Draft:     {permission: constant.PermArticlePublish, requiresReason: true},
+	},
+	model.Scheduled: {
+		model.Published: {permission: constant.PermArticlePublish},
+		model.Review:    {permission: constant.PermArticlePublish},
+		model.Draft:     {permission: constant.PermArticlePublish, requiresReason: true},
+	},
+	model.Published: {
+		model.Draft: {permission: constant.PermArticlePublish, requiresReason: true},
+	},
+}
+
+type articleWorkflowService struct {
+	articleRepo  repository.ArticleRepository
+	workflowRepo repository.ArticleWorkflowRepository
+}
+
+func NewArticleWorkflowService(articleRepo repository.ArticleRepository, workflowRepo repository.ArticleWorkflowRepository) ArticleWorkflowService {
+	return &articleWorkflowService{
+		articleRepo:  articleRepo,
+		workflowRepo: workflowRepo,
+	}
+}
+
+// **Transition Article Status**
+func (s *articleWorkflowService) TransitionArticle(articleID, actorID uuid.UUID, role model.UserRole, to model.ArticleStatus, reason string, scheduledAt *time.Time) (*model.Article, error) {
+	article, err := s.articleRepo.GetArticleByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if article == nil {
+		return nil, model.ErrArticleNotFound
+	}
+
+	transition, ok := articleTransitions[article.Status][to]
+	if !ok {
+		return nil, model.ErrInvalidStatusTransition
+	}
+
+	if !transition.allows(article, actorID, role) {
+		return nil, model.ErrTransitionNotAllowed
+	}
+
+	reason = strings.TrimSpace(reason)
+	if transition.requiresReason && reason == "" {
+		return nil, model.ErrTransitionReasonMissing
+	}
+
+	if transition.requiresSchedule && (scheduledAt == nil || !scheduledAt.After(time.Now())) {
+		return nil, model.ErrInvalidScheduleTime
+	}
+
+	history := &model.ArticleStatusHistory{
+		ArticleID:  article.ID,
+		FromStatus: article.Status,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Reason:     reason,
+	}
+
+	article.Status = to
+	article.ScheduledAt = nil
+	switch to {
+	case model.Published:
+		now := time.Now()
+		article.PublishedAt = &now
+	case model.Scheduled:
+		article.ScheduledAt = scheduledAt
+	}
+
+	if err := s.workflowRepo.TransitionArticle(article, history); err != nil {
+		if errors.Is(err, model.ErrStatusChanged) {
+			return nil, err
+		}
+		log.Println("Error transitioning article status:", err)
+		return nil, errors.New("Failed to update article status")
+	}
+
+	return article, nil
+}
+
+// **Get Article Status History**
+func (s *articleWorkflowService) GetStatusHistory(articleID, userID uuid.UUID, role model.UserRole) ([]model.ArticleStatusHistory, error) {
+	article, err := s.articleRepo.GetArticleByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if article == nil {
+		return nil, model.ErrArticleNotFound
+	}
+
+	if article.AuthorID != userID && !constant.HasPermission(role, constant.PermArticleReview) {
+		return nil, model.ErrArticleForbidden
+	}
+
+	return s.workflowRepo.ListStatusHistory(article.ID)
+}
+
+// **List Articles Waiting For Review**
+// The status filter of the request is ignored; the queue only holds
+// articles in review.
+func (s *articleWorkflowService) ListReviewQueue(req dto.ArticleListRequest) ([]model.Article, string, error) {
+	return listArticles(s.articleRepo, req, articleListing{
+		defaultSort: constant.SortCreatedAt,
+		statuses:    []model.ArticleStatus{model.Review},
+	})
+}
+
+func (t statusTransition) allows(article *model.Article, actorID uuid.UUID, role model.UserRole) bool {
+	if t.ownerOnly {
+		return article.AuthorID == actorID
+	}
+
+	return constant.HasPermission(role, t.permission)
+}
@@ -1 +1,154 @@
 package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type AuthService interface {
+	IssueSession(user *model.User) (*dto.LoginResponse, error)
+	RefreshSession(refreshToken string) (*dto.LoginResponse, error)
+	Logout(refreshToken string) error
+	LogoutAll(userID uuid.UUID) error
+}
+
+type authService struct {
+	tokenRepo repository.RefreshTokenRepository
+	cfg       *config.Config
+}
+
+func NewAuthService(tokenRepo repository.RefreshTokenRepository, cfg *config.Config) AuthService {
+	return &authService{
+		tokenRepo: tokenRepo,
+		cfg:       cfg,
+	}
+}
+
+// **Issue Session**
+// Starts a new refresh token family for a freshly authenticated user.
+func (s *authService) IssueSession(user *model.User) (*dto.LoginResponse, error) {
+	refreshToken, record, err := s.newRefreshToken(user.ID, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.CreateRefreshToken(record); err != nil {
+		log.Println("Error storing refresh token:", err)
+		return nil, errors.New("Failed to generate token")
+	}
+
+	return s.buildResponse(user, refreshToken)
+}
+
+// **Refresh Session**
+// Exchanges a refresh token for a new access/refresh pair. Presenting a
+// token that was already rotated revokes every token in its family.
+func (s *authService) RefreshSession(refreshToken string) (*dto.LoginResponse, error) {
+	current, err := s.tokenRepo.GetRefreshTokenByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		log.Println("Error getting refresh token:", err)
+		return nil, errors.New("Something went wrong, please try again later")
+	}
+
+	if current == nil {
+		return nil, model.ErrInvalidRefreshToken
+	}
+
+	if current.RevokedAt != nil {
+		s.revokeFamily(current.FamilyID)
+		return nil, model.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return nil, model.ErrInvalidRefreshToken
+	}
+
+	nextToken, next, err := s.newRefreshToken(current.UserID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.tokenRepo.RotateRefreshToken(current, next)
+	if err != nil {
+		log.Println("Error rotating refresh token:", err)
+		return nil, errors.New("Failed to generate token")
+	}
+
+	if !rotated {
+		s.revokeFamily(current.FamilyID)
+		return nil, model.ErrRefreshTokenReused
+	}
+
+	return s.buildResponse(&current.User, nextToken)
+}
+
+// **Logout**
+// Revokes the session (token family) the refresh token belongs to.
+func (s *authService) Logout(refreshToken string) error {
+	current, err := s.tokenRepo.GetRefreshTokenByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return model.ErrInvalidRefreshToken
+	}
+
+	return s.tokenRepo.RevokeFamily(current.FamilyID)
+}
+
+// **Logout All Sessions**
+func (s *authService) LogoutAll(userID uuid.UUID) error {
+	return s.tokenRepo.RevokeAllForUser(userID)
+}
+
+func (s *authService) newRefreshToken(userID, familyID uuid.UUID) (string, *model.RefreshToken, error) {
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		log.Println("Error generating refresh token:", err)
+		return "", nil, errors.New("Failed to generate token")
+	}
+
+	return token, &model.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTokenTTL),
+	}, nil
+}
+
+func (s *authService) buildResponse(user *model.User, refreshToken string) (*dto.LoginResponse, error) {
+	accessToken, expiresAt, err := utils.GenerateJWT(s.cfg, user.ID, user.Role)
+	if err != nil {
+		return nil, errors.New("Failed to generate token")
+	}
+
+	return &dto.LoginResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    &expiresAt,
+		User: &dto.UserResponse{
+			ID:            user.ID,
+			Username:      user.Username,
+			Email:         user.Email,
+			Role:          string(user.Role),
+			EmailVerified: user.EmailVerified,
+			TwoFAEnabled:  user.TwoFAEnabled,
+		},
+	}, nil
+}
+
+func (s *authService) revokeFamily(familyID uuid.UUID) {
+	if err := s.tokenRepo.RevokeFamily(familyID); err != nil {
+		log.Println("Error revoking refresh token family:", err)
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+// memoryRefreshTokenRepository keeps refresh tokens in memory with the
+// semantics of the database repository: lookups return copies, and a
+// rotation only succeeds while the old token is not revoked.
+type memoryRefreshTokenRepository struct {
+	tokens map[uuid.UUID]*model.RefreshToken
+	users  map[uuid.UUID]model.User
+
+	// beforeRotate runs just before a rotation, to simulate a concurrent
+	// request winning the race.
+	beforeRotate func(old *model.RefreshToken)
+}
+
+func newMemoryRefreshTokenRepository(users ...model.User) *memoryRefreshTokenRepository {
+	repo := &memoryRefreshTokenRepository{
+		tokens: make(map[uuid.UUID]*model.RefreshToken),
+		users:  make(map[uuid.UUID]model.User),
+	}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+	return repo
+}
+
+func (r *memoryRefreshTokenRepository) CreateRefreshToken(token *model.RefreshToken) error {
+	stored := *token
+	r.tokens[token.ID] = &stored
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) GetRefreshTokenByHash(hash string) (*model.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			found := *token
+			found.User = r.users[token.UserID]
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryRefreshTokenRepository) RotateRefreshToken(old *model.RefreshToken, next *model.RefreshToken) (bool, error) {
+	if r.beforeRotate != nil {
+		r.beforeRotate(old)
+	}
+
+	stored := r.tokens[old.ID]
+	if stored == nil || stored.RevokedAt != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	stored.RevokedAt = &now
+	stored.ReplacedByID = &next.ID
+	return true, r.CreateRefreshToken(next)
+}
+
+func (r *memoryRefreshTokenRepository) RevokeFamily(familyID uuid.UUID) error {
+	return r.revokeWhere(func(token *model.RefreshToken) bool { return token.FamilyID == familyID })
+}
+
+func (r *memoryRefreshTokenRepository) RevokeAllForUser(userID uuid.UUID) error {
+	return r.revokeWhere(func(token *model.RefreshToken) bool { return token.UserID == userID })
+}
+
+func (r *memoryRefreshTokenRepository) revokeWhere(match func(*model.RefreshToken) bool) error {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if match(token) && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) byToken(t *testing.T, token string) *model.RefreshToken {
+	t.Helper()
+	for _, stored := range r.tokens {
+		if stored.TokenHash == utils.HashToken(token) {
+			return stored
+		}
+	}
+	t.Fatalf("refresh token not stored")
+	return nil
+}
+
+func newTestAuthService(t *testing.T) (AuthService, *memoryRefreshTokenRepository, model.User) {
+	t.Helper()
+	user := model.User{ID: uuid.New(), Username: "writer", Email: "writer@example.com", Role: model.Penulis}
+	repo := newMemoryRefreshTokenRepository(user)
+	cfg := &config.Config{JWTSecret: "secret", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour}
+	return NewAuthService(repo, cfg), repo, user
+}
+
+func TestAuthServiceIssueSessionStoresHashOnly(t *testing.T) {
+	auth, repo, user := newTestAuthService(t)
+
+	session, err := auth.IssueSession(&user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Token == "" || session.RefreshToken == "" {
+		t.Fatal("IssueSession() returned an empty token")
+	}
+
+	stored := repo.byToken(t, session.RefreshToken)
+	if stored.TokenHash == session.RefreshToken {
+		t.Error("refresh token stored in plain text")
+	}
+	if stored.UserID != user.ID || stored.RevokedAt != nil {
+		t.Errorf("stored token = %+v, want an active token of the user", stored)
+	}
+}
+
+func TestAuthServiceRefreshSessionRotates(t *testing.T) {
+	auth, repo, user := newTestAuthService(t)
+
+	first, err := auth.IssueSession(&user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := auth.RefreshSession(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshSession() error = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("RefreshSession() returned the same refresh token")
+	}
+	if second.User == nil || second.User.ID != user.ID {
+		t.Errorf("RefreshSession() user = %+v, want %s", second.User, user.ID)
+	}
+
+	old, next := repo.byToken(t, first.RefreshToken), repo.byToken(t, second.RefreshToken)
+	if old.RevokedAt == nil || old.ReplacedByID == nil || *old.ReplacedByID != next.ID {
+		t.Errorf("rotated token = %+v, want it revoked and replaced by %s", old, next.ID)
+	}
+	if next.FamilyID != old.FamilyID || next.RevokedAt != nil {
+		t.Errorf("new token = %+v, want an active token in family %s", next, old.FamilyID)
+	}
+
+	if _, err := auth.RefreshSession(second.RefreshToken); err != nil {
+		t.Errorf("RefreshSession() with the rotated token error = %v", err)
+	}
+}
+
+func TestAuthServiceRefreshSessionDetectsReuse(t *testing.T) {
+	auth, repo, user := newTestAuthService(t)
+
+	first, _ := auth.IssueSession(&user)
+	other, _ := auth.IssueSession(&user)
+	second, err := auth.RefreshSession(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := auth.RefreshSession(first.RefreshToken); !errors.Is(err, model.ErrRefreshTokenReused) {
+		t.Fatalf("reusing a rotated token error = %v, want ErrRefreshTokenReused", err)
+	}
+
+	// the whole family is revoked, including the token the attacker or the
+	// legitimate client received from the rotation
+	if repo.byToken(t, second.RefreshToken).RevokedAt == nil {
+		t.Error("token issued by the rotation was not revoked")
+	}
+	if _, err := auth.RefreshSession(second.RefreshToken); !errors.Is(err, model.ErrRefreshTokenReused) {
+		t.Errorf("refreshing a revoked family error = %v, want ErrRefreshTokenReused", err)
+	}
+
+	// other sessions of the same user keep working
+	if _, err := auth.RefreshSession(other.RefreshToken); err != nil {
+		t.Errorf("refreshing another session error = %v", err)
+	}
+}
+
+func TestAuthServiceRefreshSessionConcurrentRotation(t *testing.T) {
+	auth, repo, user := newTestAuthService(t)
+
+	first, _ := auth.IssueSession(&user)
+	repo.beforeRotate = func(old *model.RefreshToken) {
+		now := time.Now()
+		repo.tokens[old.ID].RevokedAt = &now
+	}
+
+	if _, err := auth.RefreshSession(first.RefreshToken); !errors.Is(err, model.ErrRefreshTokenReused) {
+		t.Fatalf("losing a rotation race error = %v, want ErrRefreshTokenReused", err)
+	}
+
+	for _, token := range repo.tokens {
+		if token.RevokedAt == nil {
+			t.Errorf("token %s of the family is still active", token.ID)
+		}
+	}
+}
+
+func TestAuthServiceRefreshSessionRejectsInvalidTokens(t *testing.T) {
+	auth, repo, user := newTestAuthService(t)
+
+	expired, _ := auth.IssueSession(&user)
+	repo.byToken(t, expired.RefreshToken).ExpiresAt = time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"unknown", "not-a-refresh-token"},
+		{"empty", ""},
+		{"expired", expired.RefreshToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := auth.RefreshSession(tt.token); !errors.Is(err, model.ErrInvalidRefreshToken) {
+				t.Errorf("RefreshSession() error = %v, want ErrInvalidRefreshToken", err)
+			}
+		})
+	}
+}
+
+func TestAuthServiceLogout(t *testing.T) {
+	auth, _, user := newTestAuthService(t)
+
+	first, _ := auth.IssueSession(&user)
+	second, _ := auth.RefreshSession(first.RefreshToken)
+	other, _ := auth.IssueSession(&user)
+
+	if err := auth.Logout(second.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.RefreshSession(second.RefreshToken); err == nil {
+		t.Error("refresh token still works after logout")
+	}
+	if _, err := auth.RefreshSession(other.RefreshToken); err != nil {
+		t.Errorf("logout revoked another session: %v", err)
+	}
+
+	third, _ := auth.IssueSession(&user)
+	if err := auth.LogoutAll(user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.RefreshSession(third.RefreshToken); err == nil {
+		t.Error("refresh token still works after logging out of all sessions")
+	}
+}
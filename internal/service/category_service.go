@@ -1 +1,436 @@
 package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type CategoryService interface {
+	ListCategoryTree() ([]dto.CategoryResponse, error)
+	GetCategoryByID(id uuid.UUID) (*dto.CategoryResponse, error)
+	GetCategoryBySlug(slug string) (*dto.CategoryResponse, error)
+	CreateCategory(req dto.CreateCategoryRequest) (*dto.CategoryResponse, error)
+	UpdateCategory(id uuid.UUID, req dto.UpdateCategoryRequest) (*dto.CategoryResponse, error)
+	DeleteCategory(id uuid.UUID) error
+
+	GetArticleCategories(articleID, requesterID uuid.UUID, role model.UserRole) ([]dto.CategoryResponse, error)
+	SetArticleCategories(articleID, userID uuid.UUID, role model.UserRole, categoryIDs []uuid.UUID) ([]dto.CategoryResponse, error)
+}
+
+type categoryService struct {
+	repo        repository.CategoryRepository
+	articleRepo repository.ArticleRepository
+}
+
+func NewCategoryService(repo repository.CategoryRepository, articleRepo repository.ArticleRepository) CategoryService {
+	return &categoryService{
+		repo:        repo,
+		articleRepo: articleRepo,
+	}
+}
+
+// **List Category Tree**
+func (s *categoryService) ListCategoryTree() ([]dto.CategoryResponse, error) {
+	tree, err := s.loadTree()
+	if err != nil {
+		return nil, err
+	}
+
+	roots := make([]dto.CategoryResponse, 0, len(tree.children[uuid.Nil]))
+	for _, root := range tree.children[uuid.Nil] {
+		roots = append(roots, tree.subtree(root, make(map[uuid.UUID]bool)))
+	}
+	return roots, nil
+}
+
+// **Get Category By ID**
+func (s *categoryService) GetCategoryByID(id uuid.UUID) (*dto.CategoryResponse, error) {
+	category, err := s.repo.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.categoryDetail(category)
+}
+
+// **Get Category By Slug**
+func (s *categoryService) GetCategoryBySlug(slug string) (*dto.CategoryResponse, error) {
+	category, err := s.repo.GetCategoryBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.categoryDetail(category)
+}
+
+// **Create Category**
+func (s *categoryService) CreateCategory(req dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	category := &model.Category{
+		Name:        utils.CollapseWhitespace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		ParentID:    req.ParentID,
+	}
+
+	if err := s.ensureNameAvailable(category.Name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	if category.ParentID != nil {
+		parent, err := s.repo.GetCategoryByID(*category.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, model.ErrParentCategoryMissing
+		}
+	}
+
+	slug, err := s.resolveSlug(req.Slug, category)
+	if err != nil {
+		return nil, err
+	}
+	category.Slug = slug
+
+	if err := s.repo.CreateCategory(category); err != nil {
+		log.Println("Error creating category:", err)
+		return nil, errors.New("Failed to create category")
+	}
+
+	return s.GetCategoryByID(category.ID)
+}
+
+// **Update Category**
+// Moving a category below itself or one of its descendants is refused.
+func (s *categoryService) UpdateCategory(id uuid.UUID, req dto.UpdateCategoryRequest) (*dto.CategoryResponse, error) {
+	tree, err := s.loadTree()
+	if err != nil {
+		return nil, err
+	}
+
+	category, ok := tree.byID[id]
+	if !ok {
+		return nil, model.ErrCategoryNotFound
+	}
+
+	if req.Name != nil {
+		name := utils.CollapseWhitespace(*req.Name)
+		if err := s.ensureNameAvailable(name, category.ID); err != nil {
+			return nil, err
+		}
+		category.Name = name
+	}
+
+	if req.Description != nil {
+		category.Description = strings.TrimSpace(*req.Description)
+	}
+
+	if req.ParentID != nil {
+		if *req.ParentID == "" {
+			category.ParentID = nil
+		} else {
+			parentID, err := uuid.Parse(*req.ParentID)
+			if err != nil {
+				return nil, model.ErrParentCategoryMissing
+			}
+			if _, ok := tree.byID[parentID]; !ok {
+				return nil, model.ErrParentCategoryMissing
+			}
+			if tree.isWithin(parentID, category.ID) {
+				return nil, model.ErrCategoryCycle
+			}
+			category.ParentID = &parentID
+		}
+	}
+
+	// renaming keeps the slug so existing links stay valid; an empty slug
+	// asks for a fresh one derived from the name
+	if req.Slug != nil || category.Slug == "" {
+		requested := ""
+		if req.Slug != nil {
+			requested = *req.Slug
+		}
+		if category.Slug, err = s.resolveSlug(requested, category); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateCategory(category); err != nil {
+		log.Println("Error updating category:", err)
+		return nil, errors.New("Failed to update category")
+	}
+
+	return s.GetCategoryByID(category.ID)
+}
+
+// **Delete Category**
+// Child categories move up to the deleted category's parent.
+func (s *categoryService) DeleteCategory(id uuid.UUID) error {
+	category, err := s.repo.GetCategoryByID(id)
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return model.ErrCategoryNotFound
+	}
+
+	if err := s.repo.DeleteCategory(id); err != nil {
+		log.Println("Error deleting category:", err)
+		return errors.New("Failed to delete category")
+	}
+	return nil
+}
+
+// **Get Article Categories**
+func (s *categoryService) GetArticleCategories(articleID, requesterID uuid.UUID, role model.UserRole) ([]dto.CategoryResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, articleID, requesterID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.articleCategories(article.ID)
+}
+
+// **Set Article Categories**
+// Replaces the article's categories with the given ones.
+func (s *categoryService) SetArticleCategories(articleID, userID uuid.UUID, role model.UserRole, categoryIDs []uuid.UUID) ([]dto.CategoryResponse, error) {
+	article, err := findOwnedArticle(s.articleRepo, articleID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs = uniqueIDs(categoryIDs)
+	categories, err := s.repo.GetCategoriesByIDs(categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) != len(categoryIDs) {
+		return nil, model.ErrCategoryNotFound
+	}
+
+	if err := s.repo.ReplaceArticleCategories(article.ID, categoryIDs); err != nil {
+		log.Println("Error saving article categories:", err)
+		return nil, errors.New("Failed to save article categories")
+	}
+
+	return s.articleCategories(article.ID)
+}
+
+func (s *categoryService) articleCategories(articleID uuid.UUID) ([]dto.CategoryResponse, error) {
+	categories, err := s.repo.ListCategoriesByArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := s.loadTree()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		if node, ok := tree.byID[category.ID]; ok {
+			response := tree.response(node)
+			response.Breadcrumbs = tree.breadcrumbs(node)
+			responses = append(responses, response)
+		}
+	}
+	return responses, nil
+}
+
+func (s *categoryService) ensureNameAvailable(name string, categoryID uuid.UUID) error {
+	existing, err := s.repo.GetCategoryByName(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != categoryID {
+		return model.ErrCategoryNameExists
+	}
+	return nil
+}
+
+// resolveSlug validates a slug chosen by the caller, or derives a free one
+// from the name when none was given.
+func (s *categoryService) resolveSlug(requested string, category *model.Category) (string, error) {
+	if slug := utils.Slugify(requested); slug != "" {
+		existing, err := s.repo.GetCategoryBySlug(slug)
+		if err != nil {
+			return "", err
+		}
+		if existing != nil && existing.ID != category.ID {
+			return "", model.ErrCategorySlugExists
+		}
+		return slug, nil
+	}
+
+	base := utils.Slugify(category.Name)
+	if base == "" {
+		base = "category"
+	}
+	existing, err := s.repo.ListSlugsWithPrefix(base)
+	if err != nil {
+		return "", err
+	}
+	return utils.UniqueSlug(base, category.Slug, existing), nil
+}
+
+func (s *categoryService) loadTree() (*categoryTree, error) {
+	categories, err := s.repo.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	direct, total, err := s.repo.CountPublishedArticles(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCategoryTree(categories, direct, total), nil
+}
+
+// categoryDetail loads only what the detail of one category shows: its
+// ancestors for the breadcrumbs and its children, with article counts for
+// the category and its children.
+func (s *categoryService) categoryDetail(category *model.Category) (*dto.CategoryResponse, error) {
+	if category == nil {
+		return nil, model.ErrCategoryNotFound
+	}
+
+	ancestors, err := s.repo.ListCategoryAncestors(category.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.repo.ListChildCategories(category.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	counted := []uuid.UUID{category.ID}
+	for _, child := range children {
+		counted = append(counted, child.ID)
+	}
+
+	direct, total, err := s.repo.CountPublishedArticles(counted)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := append(append(ancestors, *category), children...)
+	tree := newCategoryTree(categories, direct, total)
+	return tree.detail(tree.byID[category.ID]), nil
+}
+
+// categoryTree indexes all categories for walking up (breadcrumbs, cycle
+// checks) and down (children). Categories whose parent no longer exists
+// are treated as top-level.
+type categoryTree struct {
+	byID     map[uuid.UUID]*model.Category
+	children map[uuid.UUID][]*model.Category // uuid.Nil holds the roots
+	direct   map[uuid.UUID]int64
+	total    map[uuid.UUID]int64
+}
+
+func newCategoryTree(categories []model.Category, direct, total map[uuid.UUID]int64) *categoryTree {
+	tree := &categoryTree{
+		byID:     make(map[uuid.UUID]*model.Category, len(categories)),
+		children: make(map[uuid.UUID][]*model.Category),
+		direct:   direct,
+		total:    total,
+	}
+
+	for i := range categories {
+		tree.byID[categories[i].ID] = &categories[i]
+	}
+	for i := range categories {
+		parent := uuid.Nil
+		if id := categories[i].ParentID; id != nil && tree.byID[*id] != nil {
+			parent = *id
+		}
+		tree.children[parent] = append(tree.children[parent], &categories[i])
+	}
+	return tree
+}
+
+// parent returns the parent of category, or nil for top-level categories.
+func (t *categoryTree) parent(category *model.Category) *model.Category {
+	if category.ParentID == nil {
+		return nil
+	}
+	return t.byID[*category.ParentID]
+}
+
+// isWithin reports whether id is ancestorID itself or one of its
+// descendants, by walking up from id.
+func (t *categoryTree) isWithin(id, ancestorID uuid.UUID) bool {
+	seen := make(map[uuid.UUID]bool)
+	for node := t.byID[id]; node != nil && !seen[node.ID]; node = t.parent(node) {
+		if node.ID == ancestorID {
+			return true
+		}
+		seen[node.ID] = true
+	}
+	return false
+}
+
+// breadcrumbs lists the path from the root down to category, inclusive.
+func (t *categoryTree) breadcrumbs(category *model.Category) []dto.CategoryBreadcrumb {
+	var path []dto.CategoryBreadcrumb
+	seen := make(map[uuid.UUID]bool)
+	for node := category; node != nil && !seen[node.ID]; node = t.parent(node) {
+		seen[node.ID] = true
+		path = append([]dto.CategoryBreadcrumb{{ID: node.ID, Name: node.Name, Slug: node.Slug}}, path...)
+	}
+	return path
+}
+
+func (t *categoryTree) response(category *model.Category) dto.CategoryResponse {
+	return dto.CategoryResponse{
+		ID:                category.ID,
+		Name:              category.Name,
+		Slug:              category.Slug,
+		Description:       category.Description,
+		ParentID:          category.ParentID,
+		ArticleCount:      t.direct[category.ID],
+		TotalArticleCount: t.total[category.ID],
+		CreatedAt:         category.CreatedAt,
+		UpdatedAt:         category.UpdatedAt,
+	}
+}
+
+// detail is a category with its breadcrumbs and direct children.
+func (t *categoryTree) detail(category *model.Category) *dto.CategoryResponse {
+	response := t.response(category)
+	response.Breadcrumbs = t.breadcrumbs(category)
+	for _, child := range t.children[category.ID] {
+		response.Children = append(response.Children, t.response(child))
+	}
+	return &response
+}
+
+func (t *categoryTree) subtree(category *model.Category, seen map[uuid.UUID]bool) dto.CategoryResponse {
+	seen[category.ID] = true
+	response := t.response(category)
+	for _, child := range t.children[category.ID] {
+		if !seen[child.ID] {
+			response.Children = append(response.Children, t.subtree(child, seen))
+		}
+	}
+	return response
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -1 +1,315 @@
 package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+// CreateCommentInput describes a new comment. UserID is nil for guests, who
+// identify themselves with GuestName and GuestEmail instead.
+type CreateCommentInput struct {
+	ArticleID  uuid.UUID
+	UserID     *uuid.UUID
+	Role       model.UserRole
+	Content    string
+	ParentID   *uuid.UUID
+	GuestName  string
+	GuestEmail string
+	Honeypot   string
+	IPAddress  string
+}
+
+type CommentService interface {
+	CreateComment(input CreateCommentInput) (*model.Comment, error)
+	ConfirmGuestComment(token string) (*model.Comment, error)
+	GetCommentTree(articleID uuid.UUID, page, limit int) ([]model.Comment, []model.Comment, int64, error)
+	ListModerationQueue(status model.CommentStatus, page, limit int) ([]model.Comment, int64, error)
+	ModerateComment(id, userID uuid.UUID, role model.UserRole, status model.CommentStatus) error
+	DeleteComment(id, userID uuid.UUID, role model.UserRole) error
+}
+
+type commentService struct {
+	repo        repository.CommentRepository
+	articleRepo repository.ArticleRepository
+	spamService SpamService
+	cfg         *config.Config
+}
+
+func NewCommentService(repo repository.CommentRepository, articleRepo repository.ArticleRepository, spamService SpamService, cfg *config.Config) CommentService {
+	return &commentService{
+		repo:        repo,
+		articleRepo: articleRepo,
+		spamService: spamService,
+		cfg:         cfg,
+	}
+}
+
+// **Create Comment**
+// Replies from the article author and comments from moderators skip the
+// spam checks. Everything else is scored: clean comments are published,
+// suspicious ones wait for moderation and obvious spam is filed as spam.
+// Guests may also have to confirm their email before the comment is released.
+func (s *commentService) CreateComment(input CreateCommentInput) (*model.Comment, error) {
+	article, err := s.articleRepo.GetArticleByID(input.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if article == nil || article.Status != model.Published {
+		return nil, model.ErrArticleNotFound
+	}
+
+	comment := &model.Comment{
+		ArticleID: article.ID,
+		UserID:    input.UserID,
+		Content:   input.Content,
+		Status:    model.CommentPending,
+		IPAddress: input.IPAddress,
+	}
+
+	if input.UserID == nil {
+		comment.GuestName = strings.TrimSpace(input.GuestName)
+		comment.GuestEmail = strings.ToLower(strings.TrimSpace(input.GuestEmail))
+		if comment.GuestName == "" || comment.GuestEmail == "" {
+			return nil, model.ErrGuestInfoMissing
+		}
+	} else {
+		comment.IsAuthorReply = article.AuthorID == *input.UserID
+	}
+
+	if input.ParentID != nil {
+		parent, err := s.repo.GetCommentByID(*input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if parent == nil || parent.ArticleID != article.ID {
+			return nil, model.ErrInvalidParent
+		}
+
+		rootID := parent.ID
+		if parent.RootID != nil {
+			rootID = *parent.RootID
+		}
+
+		comment.ParentID = &parent.ID
+		comment.RootID = &rootID
+	}
+
+	var confirmationToken string
+	if comment.IsAuthorReply || constant.HasPermission(input.Role, constant.PermCommentModerate) {
+		comment.Status = model.CommentApproved
+	} else {
+		verdict := s.spamService.Evaluate(SpamInput{
+			Content:   comment.Content,
+			Name:      comment.GuestName,
+			Email:     comment.GuestEmail,
+			Honeypot:  input.Honeypot,
+			IPAddress: input.IPAddress,
+		})
+
+		comment.SpamScore = verdict.Score
+		comment.SpamReasons = strings.Join(verdict.Reasons, "; ")
+		comment.Status = verdict.Status()
+
+		// no point asking spammers to confirm their email
+		if comment.IsGuest() && s.cfg.CommentEmailConfirmation && comment.Status != model.CommentSpam {
+			confirmationToken, err = utils.GenerateOpaqueToken()
+			if err != nil {
+				log.Println("Error generating comment confirmation token:", err)
+				return nil, errors.New("Failed to create comment")
+			}
+
+			comment.ConfirmationHash = utils.HashToken(confirmationToken)
+			comment.Status = model.CommentPending
+		}
+	}
+
+	if err := s.repo.CreateComment(comment); err != nil {
+		log.Println("Error creating comment:", err)
+		return nil, errors.New("Failed to create comment")
+	}
+
+	if confirmationToken != "" {
+		s.sendConfirmationEmail(comment, confirmationToken)
+	}
+
+	return s.repo.GetCommentByID(comment.ID)
+}
+
+// **Confirm Guest Comment**
+// Once confirmed, the comment gets the status its spam score earned.
+func (s *commentService) ConfirmGuestComment(token string) (*model.Comment, error) {
+	comment, err := s.repo.GetCommentByConfirmationHash(utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if comment == nil {
+		return nil, model.ErrInvalidActionToken
+	}
+
+	status := SpamVerdict{Score: comment.SpamScore}.Status()
+	if err := s.repo.ConfirmComment(comment.ID, status); err != nil {
+		log.Println("Error confirming comment:", err)
+		return nil, errors.New("Failed to confirm comment")
+	}
+
+	return s.repo.GetCommentByID(comment.ID)
+}
+
+func (s *commentService) sendConfirmationEmail(comment *model.Comment, token string) {
+	go func() {
+		data := utils.EmailData{
+			Username:         comment.GuestName,
+			VerificationLink: fmt.Sprintf("%s/api/comments/confirm?token=%s", s.cfg.BaseURL, token),
+			MindDriftEmail:   s.cfg.MindDriftEmail,
+		}
+
+		body, err := utils.GenerateEmailBody(utils.EmailCommentConfirmation, data)
+		if err != nil {
+			log.Println("Error generating email body:", err)
+			return
+		}
+
+		if err := utils.SendEmail(s.cfg, comment.GuestEmail, "Confirm Your Comment", body); err != nil {
+			log.Printf("Error sending comment confirmation to %s: %v", comment.GuestEmail, err)
+		}
+	}()
+}
+
+// **Get Comment Tree**
+// Returns one page of approved top-level comments and every approved reply
+// in those threads; the handler nests the replies under their parents.
+func (s *commentService) GetCommentTree(articleID uuid.UUID, page, limit int) ([]model.Comment, []model.Comment, int64, error) {
+	page, limit = normalizePage(page, limit)
+
+	roots, total, err := s.repo.ListRootComments(articleID, model.CommentApproved, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	rootIDs := make([]uuid.UUID, 0, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+	}
+
+	replies, err := s.repo.ListThreadReplies(rootIDs, model.CommentApproved)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return roots, replies, total, nil
+}
+
+// **List Moderation Queue**
+func (s *commentService) ListModerationQueue(status model.CommentStatus, page, limit int) ([]model.Comment, int64, error) {
+	page, limit = normalizePage(page, limit)
+	if status == "" {
+		status = model.CommentPending
+	}
+
+	return s.repo.ListCommentsByStatus(status, limit, (page-1)*limit)
+}
+
+// **Moderate Comment**
+// Moderators can moderate any comment, authors only comments on their own articles.
+func (s *commentService) ModerateComment(id, userID uuid.UUID, role model.UserRole, status model.CommentStatus) error {
+	comment, err := s.repo.GetCommentByID(id)
+	if err != nil {
+		return err
+	}
+
+	if comment == nil {
+		return model.ErrCommentNotFound
+	}
+
+	if comment.Article.AuthorID != userID && !constant.HasPermission(role, constant.PermCommentModerate) {
+		return model.ErrCommentForbidden
+	}
+
+	if err := s.repo.UpdateCommentStatus(comment.ID, status); err != nil {
+		return err
+	}
+
+	// Only moderators train the shared filter; an author's decisions on their
+	// own articles would let anyone with an account teach it.
+	if constant.HasPermission(role, constant.PermCommentModerate) {
+		s.trainSpamFilter(comment, status)
+	}
+	return nil
+}
+
+// trainSpamFilter teaches the classifier from a moderation decision:
+// approved comments are ham, spam is spam, anything else is unlearned.
+func (s *commentService) trainSpamFilter(comment *model.Comment, status model.CommentStatus) {
+	var label string
+	switch status {
+	case model.CommentApproved:
+		label = model.HamLabel
+	case model.CommentSpam:
+		label = model.SpamLabel
+	}
+
+	if label == comment.TrainedLabel {
+		return
+	}
+
+	if comment.TrainedLabel != "" {
+		if err := s.spamService.Untrain(comment.Content, comment.TrainedLabel); err != nil {
+			log.Println("Error untraining spam filter:", err)
+			return
+		}
+	}
+
+	if label != "" {
+		if err := s.spamService.Train(comment.Content, label); err != nil {
+			log.Println("Error training spam filter:", err)
+			label = ""
+		}
+	}
+
+	if err := s.repo.UpdateTrainedLabel(comment.ID, label); err != nil {
+		log.Println("Error saving spam training label:", err)
+	}
+}
+
+// **Delete Comment**
+func (s *commentService) DeleteComment(id, userID uuid.UUID, role model.UserRole) error {
+	comment, err := s.repo.GetCommentByID(id)
+	if err != nil {
+		return err
+	}
+
+	if comment == nil {
+		return model.ErrCommentNotFound
+	}
+
+	isOwner := comment.UserID != nil && *comment.UserID == userID
+	if !isOwner &&
+		comment.Article.AuthorID != userID &&
+		!constant.HasPermission(role, constant.PermCommentModerate) {
+		return model.ErrCommentForbidden
+	}
+
+	return s.repo.DeleteComment(comment.ID)
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	return page, limit
+}
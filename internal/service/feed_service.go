@@ -0,0 +1,406 @@
+package service
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+// sitemapPageSize is the protocol limit of URLs per sitemap file.
+const sitemapPageSize = 50000
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type FeedService interface {
+	Sitemap(page int) (*dto.FeedDocument, error)
+	Robots() *dto.FeedDocument
+	Feed(format, scope, slug string) (*dto.FeedDocument, error)
+}
+
+type feedService struct {
+	articleRepo  repository.ArticleRepository
+	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	seoRepo      repository.SEORepository
+	cfg          *config.Config
+}
+
+func NewFeedService(articleRepo repository.ArticleRepository, categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository, seoRepo repository.SEORepository, cfg *config.Config) FeedService {
+	return &feedService{
+		articleRepo:  articleRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		seoRepo:      seoRepo,
+		cfg:          cfg,
+	}
+}
+
+// **Sitemap**
+// Page 0 is /sitemap.xml: a plain sitemap while everything fits in one
+// file, a sitemap index pointing at the numbered pages otherwise.
+func (s *feedService) Sitemap(page int) (*dto.FeedDocument, error) {
+	lastMods, err := s.articleRepo.ListSitemapPageLastMods(sitemapPageSize)
+	if err != nil {
+		log.Println("Error loading sitemap pages:", err)
+		return nil, err
+	}
+
+	// the home page alone still makes one page
+	pages := max(len(lastMods), 1)
+	var latest time.Time
+	for _, lastMod := range lastMods {
+		if lastMod.After(latest) {
+			latest = lastMod
+		}
+	}
+
+	if page == 0 && pages > 1 {
+		index := dto.SitemapIndex{XMLNS: sitemapNS}
+		for i := range pages {
+			lastMod := lastMods[i]
+			if i == 0 {
+				lastMod = latest
+			}
+			index.Sitemaps = append(index.Sitemaps, dto.SitemapURL{
+				Loc:     fmt.Sprintf("%s/sitemaps/%d.xml", strings.TrimRight(s.cfg.BaseURL, "/"), i+1),
+				LastMod: lastMod.UTC().Format(time.RFC3339),
+			})
+		}
+		return xmlDocument(index, "application/xml; charset=utf-8", latest)
+	}
+
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 || page > pages {
+		return nil, model.ErrSitemapPageNotFound
+	}
+
+	urlSet := dto.SitemapURLSet{XMLNS: sitemapNS, URLs: []dto.SitemapURL{}}
+	limit, offset := sitemapPageSize, (page-1)*sitemapPageSize-1
+	lastModified := latest
+	if page == 1 {
+		limit, offset = sitemapPageSize-1, 0
+		home := dto.SitemapURL{Loc: strings.TrimRight(s.cfg.FrontendURL, "/") + "/"}
+		if !latest.IsZero() {
+			home.LastMod = latest.UTC().Format(time.RFC3339)
+		}
+		urlSet.URLs = append(urlSet.URLs, home)
+	} else {
+		lastModified = lastMods[page-1]
+	}
+
+	articles, err := s.articleRepo.ListSitemapEntries(limit, offset)
+	if err != nil {
+		log.Println("Error loading sitemap entries:", err)
+		return nil, err
+	}
+
+	for _, article := range articles {
+		urlSet.URLs = append(urlSet.URLs, dto.SitemapURL{
+			Loc:     ArticleURL(s.cfg, article.Slug),
+			LastMod: article.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return xmlDocument(urlSet, "application/xml; charset=utf-8", lastModified)
+}
+
+// **Robots**
+func (s *feedService) Robots() *dto.FeedDocument {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	b.WriteString("Disallow: /api/\n")
+	b.WriteString("Allow: /\n\n")
+	fmt.Fprintf(&b, "Sitemap: %s/sitemap.xml\n", strings.TrimRight(s.cfg.BaseURL, "/"))
+
+	return &dto.FeedDocument{
+		Body:        []byte(b.String()),
+		ContentType: "text/plain; charset=utf-8",
+	}
+}
+
+// feedChannel is the format independent description of a feed.
+type feedChannel struct {
+	title       string
+	description string
+	homeURL     string
+	feedURL     string
+	updated     time.Time
+	items       []feedItem
+}
+
+type feedItem struct {
+	id        string
+	url       string
+	title     string
+	summary   string
+	text      string
+	author    string
+	tags      []string
+	published time.Time
+	updated   time.Time
+}
+
+// **Feed**
+// Renders the latest published articles of the whole site, or of the
+// category or tag with the given slug, as RSS 2.0, Atom or JSON Feed.
+func (s *feedService) Feed(format, scope, slug string) (*dto.FeedDocument, error) {
+	if format != constant.FeedRSS && format != constant.FeedAtom && format != constant.FeedJSON {
+		return nil, model.ErrFeedNotFound
+	}
+
+	baseURL := strings.TrimRight(s.cfg.BaseURL, "/")
+	frontendURL := strings.TrimRight(s.cfg.FrontendURL, "/")
+	channel := feedChannel{
+		title:       s.cfg.SiteName,
+		description: s.cfg.SiteDescription,
+		homeURL:     frontendURL + "/",
+		feedURL:     baseURL + "/feeds/" + format,
+	}
+
+	var categoryID, tagID *uuid.UUID
+	switch scope {
+	case "":
+	case constant.FeedScopeCategory:
+		category, err := s.categoryRepo.GetCategoryBySlug(slug)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			return nil, model.ErrCategoryNotFound
+		}
+		categoryID = &category.ID
+		channel.title = s.cfg.SiteName + " - " + category.Name
+		channel.description = category.Description
+		channel.homeURL = frontendURL + "/categories/" + url.PathEscape(category.Slug)
+		channel.feedURL = fmt.Sprintf("%s/feeds/categories/%s/%s", baseURL, url.PathEscape(category.Slug), format)
+	case constant.FeedScopeTag:
+		tag, err := s.tagRepo.GetTagBySlug(slug)
+		if err != nil {
+			return nil, err
+		}
+		if tag == nil {
+			return nil, model.ErrTagNotFound
+		}
+		tagID = &tag.ID
+		channel.title = s.cfg.SiteName + " - #" + tag.Name
+		channel.homeURL = frontendURL + "/tags/" + url.PathEscape(tag.Slug)
+		channel.feedURL = fmt.Sprintf("%s/feeds/tags/%s/%s", baseURL, url.PathEscape(tag.Slug), format)
+	default:
+		return nil, model.ErrFeedNotFound
+	}
+
+	articles, err := s.articleRepo.ListPublishedArticles(categoryID, tagID, s.cfg.FeedItemLimit)
+	if err != nil {
+		log.Println("Error loading feed articles:", err)
+		return nil, err
+	}
+
+	channel.items, err = s.feedItems(articles)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range channel.items {
+		if item.updated.After(channel.updated) {
+			channel.updated = item.updated
+		}
+	}
+
+	switch format {
+	case constant.FeedRSS:
+		return rssDocument(channel)
+	case constant.FeedAtom:
+		return atomDocument(channel)
+	default:
+		return jsonFeedDocument(channel)
+	}
+}
+
+func (s *feedService) feedItems(articles []model.Article) ([]feedItem, error) {
+	ids := make([]uuid.UUID, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+
+	metadata, err := s.seoRepo.ListSEOMetadataByArticles(ids)
+	if err != nil {
+		log.Println("Error loading feed SEO metadata:", err)
+		return nil, err
+	}
+	descriptions := make(map[uuid.UUID]string, len(metadata))
+	for _, m := range metadata {
+		descriptions[m.ArticleID] = m.MetaDescription
+	}
+
+	items := make([]feedItem, 0, len(articles))
+	for _, article := range articles {
+		summary := descriptions[article.ID]
+		if summary == "" {
+			summary, _ = utils.TruncateText(utils.FirstParagraph(article.Content), serpDescriptionLimit)
+		}
+
+		item := feedItem{
+			id:      "urn:uuid:" + article.ID.String(),
+			url:     ArticleURL(s.cfg, article.Slug),
+			title:   article.Title,
+			summary: summary,
+			text:    utils.StripMarkup(article.Content),
+			author:  article.Author.Username,
+			updated: article.UpdatedAt,
+		}
+		if article.PublishedAt != nil {
+			item.published = *article.PublishedAt
+			if item.published.After(item.updated) {
+				item.updated = item.published
+			}
+		}
+		for _, category := range article.Category {
+			item.tags = append(item.tags, category.Name)
+		}
+		for _, tag := range article.Tags {
+			item.tags = append(item.tags, tag.Name)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func rssDocument(channel feedChannel) (*dto.FeedDocument, error) {
+	feed := dto.RSSFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: dto.RSSChannel{
+			Title:       channel.title,
+			Link:        channel.homeURL,
+			Description: channel.description,
+			SelfLink:    dto.RSSLink{Href: channel.feedURL, Rel: "self", Type: "application/rss+xml"},
+			Items:       []dto.RSSItem{},
+		},
+	}
+	if feed.Channel.Description == "" {
+		feed.Channel.Description = channel.title
+	}
+	if !channel.updated.IsZero() {
+		feed.Channel.LastBuildDate = channel.updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, item := range channel.items {
+		rssItem := dto.RSSItem{
+			Title:       item.title,
+			Link:        item.url,
+			GUID:        dto.RSSGUID{Value: item.id},
+			Description: item.summary,
+			Creator:     item.author,
+			Categories:  item.tags,
+		}
+		if !item.published.IsZero() {
+			rssItem.PubDate = item.published.UTC().Format(time.RFC1123Z)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, rssItem)
+	}
+
+	return xmlDocument(feed, "application/rss+xml; charset=utf-8", channel.updated)
+}
+
+func atomDocument(channel feedChannel) (*dto.FeedDocument, error) {
+	feed := dto.AtomFeed{
+		ID:      channel.feedURL,
+		Title:   channel.title,
+		Updated: channel.updated.UTC().Format(time.RFC3339),
+		Links: []dto.AtomLink{
+			{Href: channel.homeURL, Rel: "alternate", Type: "text/html"},
+			{Href: channel.feedURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: []dto.AtomEntry{},
+	}
+
+	for _, item := range channel.items {
+		entry := dto.AtomEntry{
+			ID:      item.id,
+			Title:   item.title,
+			Link:    dto.AtomLink{Href: item.url, Rel: "alternate", Type: "text/html"},
+			Updated: item.updated.UTC().Format(time.RFC3339),
+			Summary: item.summary,
+		}
+		if !item.published.IsZero() {
+			entry.Published = item.published.UTC().Format(time.RFC3339)
+		}
+		if item.author != "" {
+			entry.Author = &dto.AtomAuthor{Name: item.author}
+		}
+		for _, tag := range item.tags {
+			entry.Categories = append(entry.Categories, dto.AtomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return xmlDocument(feed, "application/atom+xml; charset=utf-8", channel.updated)
+}
+
+func jsonFeedDocument(channel feedChannel) (*dto.FeedDocument, error) {
+	feed := dto.JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       channel.title,
+		HomePageURL: channel.homeURL,
+		FeedURL:     channel.feedURL,
+		Description: channel.description,
+		Items:       []dto.JSONFeedItem{},
+	}
+
+	for _, item := range channel.items {
+		jsonItem := dto.JSONFeedItem{
+			ID:           item.id,
+			URL:          item.url,
+			Title:        item.title,
+			ContentText:  item.text,
+			Summary:      item.summary,
+			DateModified: item.updated.UTC().Format(time.RFC3339),
+			Tags:         item.tags,
+		}
+		if !item.published.IsZero() {
+			jsonItem.DatePublished = item.published.UTC().Format(time.RFC3339)
+		}
+		if item.author != "" {
+			jsonItem.Authors = []dto.JSONFeedAuthor{{Name: item.author}}
+		}
+		feed.Items = append(feed.Items, jsonItem)
+	}
+
+	body, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.FeedDocument{
+		Body:         body,
+		ContentType:  "application/feed+json; charset=utf-8",
+		LastModified: channel.updated,
+	}, nil
+}
+
+func xmlDocument(v any, contentType string, lastModified time.Time) (*dto.FeedDocument, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.FeedDocument{
+		Body:         append([]byte(xml.Header), body...),
+		ContentType:  contentType,
+		LastModified: lastModified,
+	}, nil
+}
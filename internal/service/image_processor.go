@@ -0,0 +1,152 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sort"
+
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+// decoding is refused above this many pixels to avoid decompression bombs
+const maxImagePixels = 40_000_000
+
+type imageEncoder struct {
+	mimeType string
+	ext      string
+	encode   func(w io.Writer, img image.Image, quality int) error
+}
+
+// imageEncoders are the formats images can be re-encoded to. There is no
+// WebP encoder in Go, so WebP uploads are re-encoded as JPEG, or PNG when
+// they have transparency, and the config rejects webp as output format.
+var imageEncoders = map[string]imageEncoder{
+	"jpeg": {
+		mimeType: "image/jpeg",
+		ext:      ".jpg",
+		encode: func(w io.Writer, img image.Image, quality int) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+		},
+	},
+	"png": {
+		mimeType: "image/png",
+		ext:      ".png",
+		encode: func(w io.Writer, img image.Image, _ int) error {
+			encoder := png.Encoder{CompressionLevel: png.BestCompression}
+			return encoder.Encode(w, img)
+		},
+	},
+}
+
+type processedImage struct {
+	data     []byte
+	mimeType string
+	ext      string
+	width    int
+	height   int
+	variants []processedVariant
+}
+
+type processedVariant struct {
+	data   []byte
+	width  int
+	height int
+}
+
+type imageProcessor struct {
+	cfg *config.Config
+}
+
+// process strips metadata by re-encoding the image, applying the EXIF
+// orientation first, caps its width and renders the configured variant
+// widths that are smaller than the image. It returns nil for GIF, which is
+// stored as uploaded to keep animations.
+func (p *imageProcessor) process(data []byte, mimeType string) (*processedImage, error) {
+	if mimeType == "image/gif" {
+		return nil, nil
+	}
+
+	header, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, model.ErrUnsupportedImageType
+	}
+	if header.Width*header.Height > maxImagePixels {
+		return nil, model.ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, model.ErrUnsupportedImageType
+	}
+
+	if mimeType == "image/jpeg" {
+		img = utils.ApplyOrientation(img, utils.JPEGOrientation(data))
+	}
+
+	encoder := p.encoderFor(mimeType, img)
+
+	if p.cfg.ImageMaxWidth > 0 && img.Bounds().Dx() > p.cfg.ImageMaxWidth {
+		img = utils.ResizeToWidth(img, p.cfg.ImageMaxWidth)
+	}
+
+	var main bytes.Buffer
+	if err := encoder.encode(&main, img, p.cfg.ImageJPEGQuality); err != nil {
+		return nil, err
+	}
+
+	result := &processedImage{
+		data:     main.Bytes(),
+		mimeType: encoder.mimeType,
+		ext:      encoder.ext,
+		width:    img.Bounds().Dx(),
+		height:   img.Bounds().Dy(),
+	}
+
+	widths := append([]int{}, p.cfg.ImageVariantWidths...)
+	sort.Ints(widths)
+	for i, width := range widths {
+		if width >= result.width || (i > 0 && width == widths[i-1]) {
+			continue
+		}
+
+		resized := utils.ResizeToWidth(img, width)
+
+		var buf bytes.Buffer
+		if err := encoder.encode(&buf, resized, p.cfg.ImageJPEGQuality); err != nil {
+			return nil, err
+		}
+
+		result.variants = append(result.variants, processedVariant{
+			data:   buf.Bytes(),
+			width:  resized.Bounds().Dx(),
+			height: resized.Bounds().Dy(),
+		})
+	}
+
+	return result, nil
+}
+
+// encoderFor keeps the upload's format unless IMAGE_OUTPUT_FORMAT asks for
+// another one. Images with transparency always stay PNG.
+func (p *imageProcessor) encoderFor(mimeType string, img image.Image) imageEncoder {
+	format := "jpeg"
+	if mimeType == "image/png" || (mimeType == "image/webp" && !utils.IsOpaque(img)) {
+		format = "png"
+	}
+
+	switch p.cfg.ImageOutputFormat {
+	case "jpeg":
+		if utils.IsOpaque(img) {
+			format = "jpeg"
+		}
+	case "png":
+		format = "png"
+	}
+
+	return imageEncoders[format]
+}
@@ -1 +1,362 @@
 package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+	"github.com/tsaqiffatih/minddrift-server/internal/storage"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+	_ "golang.org/x/image/webp"
+)
+
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// UploadImageInput is a file received by the upload endpoint.
+type UploadImageInput struct {
+	UserID    uuid.UUID
+	Role      model.UserRole
+	ArticleID *uuid.UUID
+	Data      []byte
+	AltText   string
+	Caption   string
+}
+
+type ImageService interface {
+	UploadImage(input UploadImageInput) (*model.Image, bool, error)
+	UploadImageFromURL(input UploadImageInput, rawURL string) (*model.Image, bool, error)
+	GetImageByID(id, requesterID uuid.UUID, role model.UserRole) (*model.Image, error)
+	UpdateImage(id, userID uuid.UUID, role model.UserRole, req dto.UpdateImageRequest) (*model.Image, error)
+	DeleteImage(id, userID uuid.UUID, role model.UserRole) error
+	ListUserImages(userID uuid.UUID, page, limit int) ([]model.Image, int64, error)
+	ListArticleImages(articleID, requesterID uuid.UUID, role model.UserRole, page, limit int) ([]model.Image, int64, error)
+}
+
+type imageService struct {
+	repo        repository.ImageRepository
+	articleRepo repository.ArticleRepository
+	storage     storage.Storage
+	processor   *imageProcessor
+	httpClient  *http.Client
+	cfg         *config.Config
+}
+
+func NewImageService(repo repository.ImageRepository, articleRepo repository.ArticleRepository, storage storage.Storage, cfg *config.Config) ImageService {
+	return &imageService{
+		repo:        repo,
+		articleRepo: articleRepo,
+		storage:     storage,
+		processor:   &imageProcessor{cfg: cfg},
+		httpClient:  utils.NewSafeHTTPClient(cfg.RemoteFetchTimeout, cfg.RemoteFetchMaxRedirects),
+		cfg:         cfg,
+	}
+}
+
+// **Upload Image**
+// The file type is sniffed from its content, never taken from the client.
+// Files are stored by the hash of the uploaded bytes: re-uploading the same
+// file to the same place returns the existing image (reported by the bool),
+// and identical files elsewhere share one stored object and its variants.
+func (s *imageService) UploadImage(input UploadImageInput) (*model.Image, bool, error) {
+	if int64(len(input.Data)) > s.cfg.MaxUploadSize {
+		return nil, false, model.ErrImageTooLarge
+	}
+
+	mimeType := http.DetectContentType(input.Data)
+	ext, ok := imageExtensions[mimeType]
+	if !ok {
+		return nil, false, model.ErrUnsupportedImageType
+	}
+
+	header, _, err := image.DecodeConfig(bytes.NewReader(input.Data))
+	if err != nil {
+		return nil, false, model.ErrUnsupportedImageType
+	}
+	width, height := header.Width, header.Height
+
+	if input.ArticleID != nil {
+		if _, err := findOwnedArticle(s.articleRepo, *input.ArticleID, input.UserID, input.Role); err != nil {
+			return nil, false, err
+		}
+	}
+
+	sum := sha256.Sum256(input.Data)
+	hash := hex.EncodeToString(sum[:])
+
+	existing, err := s.repo.GetUserImageByHash(input.UserID, hash, input.ArticleID)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, true, nil
+	}
+
+	image := &model.Image{
+		ContentHash: hash,
+		MimeType:    mimeType,
+		Size:        int64(len(input.Data)),
+		Width:       width,
+		Height:      height,
+		AltText:     input.AltText,
+		Caption:     input.Caption,
+		ArticleID:   input.ArticleID,
+		UploadedBy:  input.UserID,
+	}
+
+	stored, err := s.repo.GetImageByHash(hash)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if stored != nil {
+		image.StorageKey = stored.StorageKey
+		image.URL = stored.URL
+		image.MimeType = stored.MimeType
+		image.Size = stored.Size
+		image.Width = stored.Width
+		image.Height = stored.Height
+		for _, variant := range stored.Variants {
+			variant.ID = uuid.Nil
+			variant.ImageID = uuid.Nil
+			image.Variants = append(image.Variants, variant)
+		}
+	} else if err := s.storeImage(image, input.Data, ext); err != nil {
+		return nil, false, err
+	}
+
+	if err := s.repo.CreateImage(image); err != nil {
+		log.Println("Error creating image:", err)
+		return nil, false, errors.New("Failed to save image")
+	}
+
+	return image, false, nil
+}
+
+// **Upload Image From URL**
+// Downloads the image into input.Data and runs the regular upload pipeline,
+// so the payload is sniffed and size-checked like any other upload.
+func (s *imageService) UploadImageFromURL(input UploadImageInput, rawURL string) (*model.Image, bool, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || utils.ValidateRemoteURL(target) != nil {
+		return nil, false, model.ErrInvalidImageURL
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, false, model.ErrInvalidImageURL
+	}
+	req.Header.Set("Accept", "image/*")
+	req.Header.Set("User-Agent", "MindDrift-ImageFetcher/1.0")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, utils.ErrBlockedDestination) {
+			return nil, false, model.ErrInvalidImageURL
+		}
+		log.Printf("Error fetching remote image %s: %v", target.Redacted(), err)
+		return nil, false, model.ErrRemoteImageFailed
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, model.ErrRemoteImageFailed
+	}
+
+	if resp.ContentLength > s.cfg.MaxUploadSize {
+		return nil, false, model.ErrImageTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.cfg.MaxUploadSize+1))
+	if err != nil {
+		log.Printf("Error reading remote image %s: %v", target.Redacted(), err)
+		return nil, false, model.ErrRemoteImageFailed
+	}
+
+	input.Data = data
+	return s.UploadImage(input)
+}
+
+// storeImage optimizes data and writes it, with its variants, to storage
+// under keys derived from the image's content hash.
+func (s *imageService) storeImage(image *model.Image, data []byte, ext string) error {
+	processed, err := s.processor.process(data, image.MimeType)
+	if err != nil {
+		if errors.Is(err, model.ErrImageTooLarge) || errors.Is(err, model.ErrUnsupportedImageType) {
+			return err
+		}
+		log.Println("Error processing image:", err)
+		return errors.New("Failed to process image")
+	}
+
+	prefix := "images/" + image.ContentHash[:2] + "/" + image.ContentHash
+	if processed != nil {
+		data, ext = processed.data, processed.ext
+		image.MimeType = processed.mimeType
+		image.Size = int64(len(processed.data))
+		image.Width = processed.width
+		image.Height = processed.height
+
+		for _, variant := range processed.variants {
+			key := fmt.Sprintf("%s-%dw%s", prefix, variant.width, ext)
+			if err := s.storage.Put(context.Background(), key, variant.data, image.MimeType); err != nil {
+				log.Println("Error storing image variant:", err)
+				return errors.New("Failed to store image")
+			}
+
+			image.Variants = append(image.Variants, model.ImageVariant{
+				Width:      variant.width,
+				Height:     variant.height,
+				MimeType:   image.MimeType,
+				Size:       int64(len(variant.data)),
+				StorageKey: key,
+				URL:        s.storage.URL(key),
+			})
+		}
+	}
+
+	image.StorageKey = prefix + ext
+	if err := s.storage.Put(context.Background(), image.StorageKey, data, image.MimeType); err != nil {
+		log.Println("Error storing image:", err)
+		return errors.New("Failed to store image")
+	}
+	image.URL = s.storage.URL(image.StorageKey)
+
+	return nil
+}
+
+// **Get Image By ID**
+// Images attached to an article are only shown to those who may read the
+// article; for anyone else they do not exist.
+func (s *imageService) GetImageByID(id, requesterID uuid.UUID, role model.UserRole) (*model.Image, error) {
+	image, err := s.repo.GetImageByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if image == nil {
+		return nil, model.ErrImageNotFound
+	}
+
+	if image.ArticleID != nil {
+		if _, err := findVisibleArticle(s.articleRepo, *image.ArticleID, requesterID, role); err != nil {
+			if errors.Is(err, model.ErrArticleNotFound) {
+				return nil, model.ErrImageNotFound
+			}
+			return nil, err
+		}
+	}
+
+	return image, nil
+}
+
+// **Update Image**
+func (s *imageService) UpdateImage(id, userID uuid.UUID, role model.UserRole, req dto.UpdateImageRequest) (*model.Image, error) {
+	image, err := s.getOwnedImage(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AltText != nil {
+		image.AltText = *req.AltText
+	}
+	if req.Caption != nil {
+		image.Caption = *req.Caption
+	}
+
+	if err := s.repo.UpdateImage(image); err != nil {
+		log.Println("Error updating image:", err)
+		return nil, errors.New("Failed to update image")
+	}
+
+	return image, nil
+}
+
+// **Delete Image**
+// The stored object is removed once no other image shares it.
+func (s *imageService) DeleteImage(id, userID uuid.UUID, role model.UserRole) error {
+	image, err := s.getOwnedImage(id, userID, role)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteImage(image.ID); err != nil {
+		return err
+	}
+
+	remaining, err := s.repo.CountImagesByHash(image.ContentHash)
+	if err != nil {
+		log.Println("Error counting images by hash:", err)
+		return nil
+	}
+
+	if remaining == 0 {
+		keys := []string{image.StorageKey}
+		for _, variant := range image.Variants {
+			keys = append(keys, variant.StorageKey)
+		}
+
+		for _, key := range keys {
+			if err := s.storage.Delete(context.Background(), key); err != nil {
+				log.Println("Error deleting stored image:", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// **List User Images**
+func (s *imageService) ListUserImages(userID uuid.UUID, page, limit int) ([]model.Image, int64, error) {
+	page, limit = normalizePage(page, limit)
+	return s.repo.ListImagesByUser(userID, limit, (page-1)*limit)
+}
+
+// **List Article Images**
+func (s *imageService) ListArticleImages(articleID, requesterID uuid.UUID, role model.UserRole, page, limit int) ([]model.Image, int64, error) {
+	article, err := findVisibleArticle(s.articleRepo, articleID, requesterID, role)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, limit = normalizePage(page, limit)
+	return s.repo.ListImagesByArticle(article.ID, limit, (page-1)*limit)
+}
+
+func (s *imageService) getOwnedImage(id, userID uuid.UUID, role model.UserRole) (*model.Image, error) {
+	image, err := s.repo.GetImageByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if image == nil {
+		return nil, model.ErrImageNotFound
+	}
+
+	if image.UploadedBy != userID && !constant.HasPermission(role, constant.PermArticleManageAny) {
+		return nil, model.ErrImageForbidden
+	}
+
+	return image, nil
+}
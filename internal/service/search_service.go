@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"html"
+	"log"
+	"strings"
+
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+var searchHighlighter = strings.NewReplacer(
+	repository.SearchHighlightStart, "<mark>",
+	repository.SearchHighlightStop, "</mark>",
+)
+
+type SearchService interface {
+	SearchArticles(req dto.ArticleSearchRequest) (*dto.PageResponse[dto.ArticleSearchResult], error)
+}
+
+type searchService struct {
+	repo repository.SearchRepository
+	cfg  *config.Config
+}
+
+func NewSearchService(repo repository.SearchRepository, cfg *config.Config) SearchService {
+	return &searchService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// **Search Articles**
+// Searches published articles, ranking title matches above tag matches
+// above body matches. The query accepts web search syntax: "quoted
+// phrases", OR and -excluded words.
+func (s *searchService) SearchArticles(req dto.ArticleSearchRequest) (*dto.PageResponse[dto.ArticleSearchResult], error) {
+	page, limit := normalizePage(req.Page, req.Limit)
+
+	params := repository.ArticleSearchParams{
+		Query:      strings.TrimSpace(req.Query),
+		CategoryID: parseOptionalID(req.CategoryID),
+		TagID:      parseOptionalID(req.TagID),
+		AuthorID:   parseOptionalID(req.AuthorID),
+		Limit:      limit,
+		Offset:     (page - 1) * limit,
+	}
+
+	var err error
+	if params.From, params.To, err = parseDateRange(req.From, req.To); err != nil {
+		return nil, err
+	}
+
+	hits, total, err := s.repo.SearchArticles(params)
+	if err != nil {
+		log.Println("Error searching articles:", err)
+		return nil, errors.New("Failed to search articles")
+	}
+
+	results := make([]dto.ArticleSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, dto.ArticleSearchResult{
+			ID:             hit.ID,
+			Title:          hit.Title,
+			TitleHighlight: highlightSearchText(hit.Title, hit.TitleHighlight),
+			Snippet:        highlightSearchText("", hit.Snippet),
+			Slug:           hit.Slug,
+			URL:            ArticleURL(s.cfg, hit.Slug),
+			Author:         dto.AuthorResponse{ID: hit.AuthorID, Username: hit.Username},
+			PublishedAt:    hit.PublishedAt,
+			Rank:           hit.Rank,
+		})
+	}
+
+	response := dto.NewOffsetPage(results, page, limit, total)
+	return &response, nil
+}
+
+// highlightSearchText turns a ts_headline fragment into safe HTML. The
+// fragment is still Markdown or entity encoded text, so it is reduced to
+// plain text first, then escaped, and only then are the markers replaced.
+func highlightSearchText(fallback, fragment string) string {
+	text := utils.CollapseWhitespace(utils.StripMarkup(fragment))
+	if text == "" {
+		text = fallback
+	}
+	return searchHighlighter.Replace(html.EscapeString(text))
+}
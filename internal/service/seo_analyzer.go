@@ -0,0 +1,423 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+var (
+	htmlHeadingTag = regexp.MustCompile(`(?i)<h([1-6])[\s>]`)
+	mdHeadingLine  = regexp.MustCompile(`(?m)^\s{0,3}(#{1,6})\s+\S`)
+	htmlLinkTag    = regexp.MustCompile(`(?i)<a\s[^>]*href\s*=\s*["']([^"']+)["']`)
+	mdLinkTarget   = regexp.MustCompile(`(^|[^!])\[[^\]]*\]\(([^)\s]+)[^)]*\)`)
+	htmlImageTag   = regexp.MustCompile(`(?i)<img\s[^>]*>`)
+	htmlAltAttr    = regexp.MustCompile(`(?i)\salt\s*=\s*["']([^"']*)["']`)
+	htmlSrcAttr    = regexp.MustCompile(`(?i)\ssrc\s*=\s*["']([^"']*)["']`)
+	mdImage        = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]*)`)
+	sentenceEnd    = regexp.MustCompile(`[.!?]+(\s|$)`)
+)
+
+// SEOAnalysisInput is everything the analyzer scores.
+type SEOAnalysisInput struct {
+	Title           string
+	Slug            string
+	Content         string
+	MetaTitle       string
+	MetaDescription string
+	Keyword         string
+	Images          []model.Image
+	SiteHosts       []string // hosts whose links count as internal
+}
+
+type seoCheck struct {
+	id       string
+	label    string
+	maxScore int
+	run      func(a *seoAnalysis) (score int, message, suggestion string)
+}
+
+// seoChecks add up to 100 points.
+var seoChecks = []seoCheck{
+	{"keyword_in_title", "Focus keyword in title", 10, checkKeywordInTitle},
+	{"keyword_in_slug", "Focus keyword in slug", 5, checkKeywordInSlug},
+	{"keyword_in_intro", "Focus keyword in first paragraph", 10, checkKeywordInIntro},
+	{"keyword_density", "Keyword density", 10, checkKeywordDensity},
+	{"keyword_in_description", "Focus keyword in meta description", 5, checkKeywordInDescription},
+	{"meta_title_length", "Meta title length", 5, checkMetaTitleLength},
+	{"meta_description_length", "Meta description length", 10, checkMetaDescriptionLength},
+	{"headings", "Heading structure", 10, checkHeadings},
+	{"content_length", "Content length", 10, checkContentLength},
+	{"image_alt_text", "Image alt text", 10, checkImageAltText},
+	{"links", "Internal and external links", 5, checkLinks},
+	{"readability", "Readability", 10, checkReadability},
+}
+
+// seoAnalysis holds the input together with the facts derived from it.
+type seoAnalysis struct {
+	input         SEOAnalysisInput
+	keyword       string
+	text          string
+	words         []string
+	sentences     []string
+	headings      []int
+	internalLinks int
+	externalLinks int
+	imageCount    int
+	imagesWithAlt int
+	density       float64
+}
+
+// AnalyzeSEO scores an article from 0 to 100 and explains every point it
+// did not award.
+func AnalyzeSEO(input SEOAnalysisInput) dto.SEOScoreResponse {
+	a := newSEOAnalysis(input)
+
+	response := dto.SEOScoreResponse{
+		Keyword:        a.keyword,
+		WordCount:      len(a.words),
+		KeywordDensity: math.Round(a.density*100) / 100,
+		InternalLinks:  a.internalLinks,
+		ExternalLinks:  a.externalLinks,
+		Checks:         make([]dto.SEOCheckResult, 0, len(seoChecks)),
+		Suggestions:    []string{},
+	}
+
+	for _, check := range seoChecks {
+		score, message, suggestion := check.run(a)
+		score = min(max(score, 0), check.maxScore)
+
+		response.Score += score
+		response.Checks = append(response.Checks, dto.SEOCheckResult{
+			ID:         check.id,
+			Label:      check.label,
+			Score:      score,
+			MaxScore:   check.maxScore,
+			Passed:     score == check.maxScore,
+			Message:    message,
+			Suggestion: suggestion,
+		})
+		if suggestion != "" {
+			response.Suggestions = append(response.Suggestions, suggestion)
+		}
+	}
+
+	return response
+}
+
+func newSEOAnalysis(input SEOAnalysisInput) *seoAnalysis {
+	a := &seoAnalysis{
+		input:   input,
+		keyword: strings.ToLower(utils.CollapseWhitespace(input.Keyword)),
+		text:    utils.StripMarkup(input.Content),
+	}
+
+	a.words = strings.Fields(a.text)
+	for _, sentence := range sentenceEnd.Split(a.text, -1) {
+		if sentence = strings.TrimSpace(sentence); sentence != "" {
+			a.sentences = append(a.sentences, sentence)
+		}
+	}
+
+	for _, match := range htmlHeadingTag.FindAllStringSubmatch(input.Content, -1) {
+		a.headings = append(a.headings, int(match[1][0]-'0'))
+	}
+	for _, match := range mdHeadingLine.FindAllStringSubmatch(input.Content, -1) {
+		a.headings = append(a.headings, len(match[1]))
+	}
+
+	var links []string
+	for _, match := range htmlLinkTag.FindAllStringSubmatch(input.Content, -1) {
+		links = append(links, match[1])
+	}
+	for _, match := range mdLinkTarget.FindAllStringSubmatch(input.Content, -1) {
+		links = append(links, match[2])
+	}
+	for _, link := range links {
+		switch classifyLink(link, input.SiteHosts) {
+		case "internal":
+			a.internalLinks++
+		case "external":
+			a.externalLinks++
+		}
+	}
+
+	// images embedded in the content, then uploaded images not embedded
+	inline := make(map[string]bool)
+	addImage := func(src, alt string) {
+		inline[src] = true
+		a.imageCount++
+		if strings.TrimSpace(alt) != "" {
+			a.imagesWithAlt++
+		}
+	}
+	for _, tag := range htmlImageTag.FindAllString(input.Content, -1) {
+		var src, alt string
+		if match := htmlSrcAttr.FindStringSubmatch(tag); match != nil {
+			src = match[1]
+		}
+		if match := htmlAltAttr.FindStringSubmatch(tag); match != nil {
+			alt = match[1]
+		}
+		addImage(src, alt)
+	}
+	for _, match := range mdImage.FindAllStringSubmatch(input.Content, -1) {
+		addImage(match[2], match[1])
+	}
+	for _, image := range input.Images {
+		if !inline[image.URL] {
+			addImage(image.URL, image.AltText)
+		}
+	}
+
+	if a.keyword != "" && len(a.words) > 0 {
+		pattern := regexp.MustCompile(`(^|[^\p{L}\p{N}])` + regexp.QuoteMeta(a.keyword) + `($|[^\p{L}\p{N}])`)
+		occurrences := len(pattern.FindAllStringIndex(strings.ToLower(utils.CollapseWhitespace(a.text)), -1))
+		a.density = float64(occurrences*len(strings.Fields(a.keyword))) / float64(len(a.words)) * 100
+	}
+
+	return a
+}
+
+func (a *seoAnalysis) contains(text string) bool {
+	return a.keyword != "" && strings.Contains(strings.ToLower(text), a.keyword)
+}
+
+const noKeywordSuggestion = "Set a focus keyword in the SEO keywords so it can be checked."
+
+func checkKeywordInTitle(a *seoAnalysis) (int, string, string) {
+	if a.keyword == "" {
+		return 0, "No focus keyword set.", noKeywordSuggestion
+	}
+	if a.contains(a.input.Title) || a.contains(a.input.MetaTitle) {
+		return 10, "The title contains the focus keyword.", ""
+	}
+	return 0, "The title does not contain the focus keyword.", fmt.Sprintf("Use %q in the title, ideally near the beginning.", a.keyword)
+}
+
+func checkKeywordInSlug(a *seoAnalysis) (int, string, string) {
+	if a.keyword == "" {
+		return 0, "No focus keyword set.", ""
+	}
+	if strings.Contains(a.input.Slug, utils.Slugify(a.keyword)) {
+		return 5, "The slug contains the focus keyword.", ""
+	}
+	return 0, "The slug does not contain the focus keyword.", "Include the focus keyword in the slug."
+}
+
+func checkKeywordInIntro(a *seoAnalysis) (int, string, string) {
+	if a.keyword == "" {
+		return 0, "No focus keyword set.", ""
+	}
+	if a.contains(utils.FirstParagraph(a.input.Content)) {
+		return 10, "The first paragraph mentions the focus keyword.", ""
+	}
+	return 0, "The first paragraph does not mention the focus keyword.", "Mention the focus keyword in the first paragraph."
+}
+
+// checkKeywordDensity expects the keyword to make up 0.5% to 2.5% of the text.
+func checkKeywordDensity(a *seoAnalysis) (int, string, string) {
+	if a.keyword == "" {
+		return 0, "No focus keyword set.", ""
+	}
+
+	message := fmt.Sprintf("Keyword density is %.2f%%.", a.density)
+	switch {
+	case a.density == 0:
+		return 0, "The focus keyword does not appear in the content.", "Use the focus keyword in the body text."
+	case a.density < 0.5:
+		return 5, message, "Use the focus keyword a few more times; aim for 0.5% to 2.5%."
+	case a.density > 2.5:
+		return 3, message, "The focus keyword is overused; rephrase some occurrences to stay below 2.5%."
+	default:
+		return 10, message, ""
+	}
+}
+
+func checkKeywordInDescription(a *seoAnalysis) (int, string, string) {
+	if a.keyword == "" {
+		return 0, "No focus keyword set.", ""
+	}
+	if a.contains(a.input.MetaDescription) {
+		return 5, "The meta description contains the focus keyword.", ""
+	}
+	return 0, "The meta description does not contain the focus keyword.", "Mention the focus keyword in the meta description."
+}
+
+func checkMetaTitleLength(a *seoAnalysis) (int, string, string) {
+	length := utf8.RuneCountInString(a.input.MetaTitle)
+	message := fmt.Sprintf("The meta title is %d characters long.", length)
+	switch {
+	case length < 30:
+		return 2, message, "Make the meta title longer; 30 to 60 characters works best."
+	case length > serpTitleLimit:
+		return 2, message, "Shorten the meta title to 60 characters so it is not truncated in search results."
+	default:
+		return 5, message, ""
+	}
+}
+
+func checkMetaDescriptionLength(a *seoAnalysis) (int, string, string) {
+	length := utf8.RuneCountInString(a.input.MetaDescription)
+	message := fmt.Sprintf("The meta description is %d characters long.", length)
+	switch {
+	case length == 0:
+		return 0, "There is no meta description.", "Write a meta description of 70 to 160 characters."
+	case length < 70:
+		return 5, message, "Make the meta description longer; 70 to 160 characters works best."
+	case length > serpDescriptionLimit:
+		return 5, message, "Shorten the meta description to 160 characters so it is not truncated."
+	default:
+		return 10, message, ""
+	}
+}
+
+// checkHeadings wants subheadings, no extra H1 (the title is the H1) and no
+// skipped levels such as an H4 directly after an H2.
+func checkHeadings(a *seoAnalysis) (int, string, string) {
+	if len(a.headings) == 0 {
+		if len(a.words) < 300 {
+			return 10, "Short article without subheadings.", ""
+		}
+		return 3, "The article has no subheadings.", "Break the content up with H2 and H3 subheadings."
+	}
+
+	score := 10
+	var suggestions []string
+
+	for _, level := range a.headings {
+		if level == 1 {
+			score -= 4
+			suggestions = append(suggestions, "Use H2 for sections; the article title is already the H1.")
+			break
+		}
+	}
+
+	previous := 1
+	for _, level := range a.headings {
+		if level > previous+1 {
+			score -= 3
+			suggestions = append(suggestions, fmt.Sprintf("Do not skip heading levels (H%d follows H%d).", level, previous))
+			break
+		}
+		previous = level
+	}
+
+	return score, fmt.Sprintf("The article has %d subheadings.", len(a.headings)), strings.Join(suggestions, " ")
+}
+
+func checkContentLength(a *seoAnalysis) (int, string, string) {
+	count := len(a.words)
+	message := fmt.Sprintf("The article has %d words.", count)
+	switch {
+	case count >= 600:
+		return 10, message, ""
+	case count >= 300:
+		return 8, message, "Articles of 600 words or more tend to rank better."
+	case count >= 150:
+		return 4, message, "The article is short; aim for at least 300 words."
+	default:
+		return 0, message, "The article is very short; aim for at least 300 words."
+	}
+}
+
+func checkImageAltText(a *seoAnalysis) (int, string, string) {
+	if a.imageCount == 0 {
+		return 5, "The article has no images.", "Add at least one relevant image with descriptive alt text."
+	}
+
+	missing := a.imageCount - a.imagesWithAlt
+	message := fmt.Sprintf("%d of %d images have alt text.", a.imagesWithAlt, a.imageCount)
+	if missing == 0 {
+		return 10, message, ""
+	}
+	return int(10 * float64(a.imagesWithAlt) / float64(a.imageCount)), message,
+		fmt.Sprintf("Add alt text to the %d images that have none.", missing)
+}
+
+func checkLinks(a *seoAnalysis) (int, string, string) {
+	message := fmt.Sprintf("%d internal and %d external links.", a.internalLinks, a.externalLinks)
+
+	score := 0
+	var suggestions []string
+	if a.internalLinks > 0 {
+		score += 3
+	} else {
+		suggestions = append(suggestions, "Link to related articles on this site.")
+	}
+	if a.externalLinks > 0 {
+		score += 2
+	} else {
+		suggestions = append(suggestions, "Link to at least one authoritative external source.")
+	}
+
+	return score, message, strings.Join(suggestions, " ")
+}
+
+// checkReadability looks at sentence length, which works for both
+// Indonesian and English text, unlike syllable based formulas.
+func checkReadability(a *seoAnalysis) (int, string, string) {
+	if len(a.sentences) == 0 {
+		return 0, "There is no text to assess.", "Add body text to the article."
+	}
+
+	var long int
+	for _, sentence := range a.sentences {
+		if len(strings.Fields(sentence)) > 25 {
+			long++
+		}
+	}
+
+	average := float64(len(a.words)) / float64(len(a.sentences))
+	longShare := float64(long) / float64(len(a.sentences)) * 100
+	message := fmt.Sprintf("Sentences average %.1f words; %.0f%% are longer than 25 words.", average, longShare)
+
+	score := 10
+	var suggestions []string
+	if average > 25 {
+		score -= 5
+		suggestions = append(suggestions, "Shorten your sentences; aim for an average under 20 words.")
+	} else if average > 20 {
+		score -= 2
+		suggestions = append(suggestions, "Sentences are on the long side; aim for an average under 20 words.")
+	}
+	if longShare > 25 {
+		score -= 3
+		suggestions = append(suggestions, "Split up sentences longer than 25 words.")
+	}
+
+	return score, message, strings.Join(suggestions, " ")
+}
+
+// classifyLink returns "internal", "external" or "" for anchors and other
+// links that do not leave the page.
+func classifyLink(link string, siteHosts []string) string {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.HasPrefix(link, "#") || strings.HasPrefix(link, "mailto:") || strings.HasPrefix(link, "tel:") {
+		return ""
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	if u.Host == "" {
+		return "internal"
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	for _, siteHost := range siteHosts {
+		if host == strings.TrimPrefix(strings.ToLower(siteHost), "www.") {
+			return "internal"
+		}
+	}
+	return "external"
+}
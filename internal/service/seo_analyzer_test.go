@@ -0,0 +1,183 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+)
+
+func TestAnalyzeSEO(t *testing.T) {
+	sentence := "Golang makes small services easy to build and test. "
+	filler := "Readers like short and clear sentences about code. "
+
+	tests := []struct {
+		name          string
+		input         SEOAnalysisInput
+		wantChecks    map[string]int
+		wantInternal  int
+		wantExternal  int
+		wantWordCount int
+	}{
+		{
+			name: "empty article",
+			wantChecks: map[string]int{
+				"keyword_in_title":        0,
+				"keyword_in_slug":         0,
+				"keyword_in_intro":        0,
+				"keyword_density":         0,
+				"keyword_in_description":  0,
+				"meta_title_length":       2,
+				"meta_description_length": 0,
+				"headings":                10,
+				"content_length":          0,
+				"image_alt_text":          5,
+				"links":                   0,
+				"readability":             0,
+			},
+		},
+		{
+			name: "keyword placement",
+			input: SEOAnalysisInput{
+				Title:           "Learning Golang Basics",
+				Slug:            "learning-golang-basics",
+				Content:         sentence + strings.Repeat(filler, 40),
+				MetaTitle:       "Learning Golang Basics: a practical guide for beginners",
+				MetaDescription: "A practical introduction to Golang for developers who want to build small, well tested services.",
+				Keyword:         " Golang ",
+			},
+			wantChecks: map[string]int{
+				"keyword_in_title":        10,
+				"keyword_in_slug":         5,
+				"keyword_in_intro":        10,
+				"keyword_density":         5,
+				"keyword_in_description":  5,
+				"meta_title_length":       5,
+				"meta_description_length": 10,
+				"readability":             10,
+			},
+			wantWordCount: 9 + 40*8,
+		},
+		{
+			name: "keyword missing",
+			input: SEOAnalysisInput{
+				Title:   "Learning Rust",
+				Slug:    "learning-rust",
+				Content: strings.Repeat(filler, 10),
+				Keyword: "golang",
+			},
+			wantChecks: map[string]int{
+				"keyword_in_title":       0,
+				"keyword_in_slug":        0,
+				"keyword_in_intro":       0,
+				"keyword_density":        0,
+				"keyword_in_description": 0,
+			},
+			wantWordCount: 80,
+		},
+		{
+			name: "keyword overused",
+			input: SEOAnalysisInput{
+				Content: strings.Repeat(sentence, 5),
+				Keyword: "golang",
+			},
+			wantChecks:    map[string]int{"keyword_density": 3},
+			wantWordCount: 45,
+		},
+		{
+			name: "headings with h1 and skipped level",
+			input: SEOAnalysisInput{
+				Content: "# Title\n\nIntro.\n\n## Section\n\nText.\n\n#### Detail\n\nMore.",
+			},
+			wantChecks:    map[string]int{"headings": 3},
+			wantWordCount: 6,
+		},
+		{
+			name: "long article without headings",
+			input: SEOAnalysisInput{
+				Content: strings.Repeat(filler, 80),
+			},
+			wantChecks:    map[string]int{"headings": 3, "content_length": 10},
+			wantWordCount: 640,
+		},
+		{
+			name: "links",
+			input: SEOAnalysisInput{
+				Content:   `See [about](/about), [docs](https://www.minddrift.id/docs), <a href="https://go.dev">Go</a>, [top](#top) and ![logo](/logo.png).`,
+				SiteHosts: []string{"minddrift.id"},
+			},
+			wantChecks:   map[string]int{"links": 5},
+			wantInternal: 2,
+			wantExternal: 1,
+		},
+		{
+			name: "external links only",
+			input: SEOAnalysisInput{
+				Content:   "Read [the spec](https://go.dev/ref/spec).",
+				SiteHosts: []string{"minddrift.id"},
+			},
+			wantChecks:    map[string]int{"links": 2},
+			wantExternal:  1,
+			wantWordCount: 3,
+		},
+		{
+			name: "image alt text",
+			input: SEOAnalysisInput{
+				Content: `![](/a.png) <img src="/b.png" alt="B">`,
+				Images: []model.Image{
+					{URL: "/b.png"},
+					{URL: "/c.png", AltText: "C"},
+				},
+			},
+			wantChecks: map[string]int{"image_alt_text": 6},
+		},
+		{
+			name: "long sentences",
+			input: SEOAnalysisInput{
+				Content: strings.Repeat("word ", 30) + "end.",
+			},
+			wantChecks:    map[string]int{"readability": 2},
+			wantWordCount: 31,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnalyzeSEO(tt.input)
+
+			scores := make(map[string]int, len(got.Checks))
+			total, maxTotal := 0, 0
+			for _, check := range got.Checks {
+				scores[check.ID] = check.Score
+				total += check.Score
+				maxTotal += check.MaxScore
+				if check.Passed != (check.Score == check.MaxScore) {
+					t.Errorf("check %s passed = %v with score %d/%d", check.ID, check.Passed, check.Score, check.MaxScore)
+				}
+			}
+			if maxTotal != 100 {
+				t.Errorf("checks add up to %d points, want 100", maxTotal)
+			}
+			if got.Score != total {
+				t.Errorf("Score = %d, want the sum of the checks %d", got.Score, total)
+			}
+
+			for id, want := range tt.wantChecks {
+				score, ok := scores[id]
+				if !ok {
+					t.Errorf("check %s missing", id)
+				} else if score != want {
+					t.Errorf("check %s score = %d, want %d", id, score, want)
+				}
+			}
+
+			if got.InternalLinks != tt.wantInternal || got.ExternalLinks != tt.wantExternal {
+				t.Errorf("links = %d internal, %d external, want %d, %d",
+					got.InternalLinks, got.ExternalLinks, tt.wantInternal, tt.wantExternal)
+			}
+			if tt.wantWordCount != 0 && got.WordCount != tt.wantWordCount {
+				t.Errorf("WordCount = %d, want %d", got.WordCount, tt.wantWordCount)
+			}
+		})
+	}
+}
@@ -1 +1,218 @@
 package service
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+// Search engines cut titles and descriptions by pixel width; these are the
+// usual character approximations.
+const (
+	serpTitleLimit       = 60
+	serpDescriptionLimit = 160
+)
+
+type SEOService interface {
+	GetSEOMetadata(articleID, requesterID uuid.UUID, role model.UserRole) (*dto.SEOMetadataResponse, error)
+	UpdateSEOMetadata(articleID, userID uuid.UUID, role model.UserRole, req dto.SEOMetadataRequest) (*dto.SEOMetadataResponse, error)
+	DeleteSEOMetadata(articleID, userID uuid.UUID, role model.UserRole) error
+	PreviewSearchResult(articleID, requesterID uuid.UUID, role model.UserRole) (*dto.SEOPreviewResponse, error)
+	ScoreArticle(articleID, requesterID uuid.UUID, role model.UserRole, keyword string) (*dto.SEOScoreResponse, error)
+	GetSocialMetadata(articleID, requesterID uuid.UUID, role model.UserRole) (*dto.SocialMetadataResponse, error)
+}
+
+type seoService struct {
+	repo        repository.SEORepository
+	articleRepo repository.ArticleRepository
+	imageRepo   repository.ImageRepository
+	cfg         *config.Config
+}
+
+func NewSEOService(repo repository.SEORepository, articleRepo repository.ArticleRepository, imageRepo repository.ImageRepository, cfg *config.Config) SEOService {
+	return &seoService{
+		repo:        repo,
+		articleRepo: articleRepo,
+		imageRepo:   imageRepo,
+		cfg:         cfg,
+	}
+}
+
+// **Get SEO Metadata**
+func (s *seoService) GetSEOMetadata(articleID, requesterID uuid.UUID, role model.UserRole) (*dto.SEOMetadataResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, articleID, requesterID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.resolveMetadata(article)
+}
+
+// **Update SEO Metadata**
+func (s *seoService) UpdateSEOMetadata(articleID, userID uuid.UUID, role model.UserRole, req dto.SEOMetadataRequest) (*dto.SEOMetadataResponse, error) {
+	article, err := findOwnedArticle(s.articleRepo, articleID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	keywords := make([]string, 0, len(req.Keywords))
+	seen := make(map[string]bool)
+	for _, keyword := range req.Keywords {
+		keyword = utils.CollapseWhitespace(strings.ReplaceAll(keyword, ",", " "))
+		if keyword != "" && !seen[strings.ToLower(keyword)] {
+			seen[strings.ToLower(keyword)] = true
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	metadata := &model.SEOMetadata{
+		ArticleID:       article.ID,
+		MetaTitle:       strings.TrimSpace(req.MetaTitle),
+		MetaDescription: utils.CollapseWhitespace(req.MetaDescription),
+		Keywords:        strings.Join(keywords, ","),
+	}
+
+	if err := s.repo.SaveSEOMetadata(metadata); err != nil {
+		log.Println("Error saving SEO metadata:", err)
+		return nil, errors.New("Failed to save SEO metadata")
+	}
+
+	return s.resolveMetadata(article)
+}
+
+// **Delete SEO Metadata**
+// The article falls back to generated metadata.
+func (s *seoService) DeleteSEOMetadata(articleID, userID uuid.UUID, role model.UserRole) error {
+	article, err := findOwnedArticle(s.articleRepo, articleID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteSEOMetadata(article.ID)
+}
+
+// **Preview Search Result**
+func (s *seoService) PreviewSearchResult(articleID, requesterID uuid.UUID, role model.UserRole) (*dto.SEOPreviewResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, articleID, requesterID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := s.resolveMetadata(article)
+	if err != nil {
+		return nil, err
+	}
+
+	title, titleTruncated := utils.TruncateText(metadata.MetaTitle, serpTitleLimit)
+	description, descriptionTruncated := utils.TruncateText(metadata.MetaDescription, serpDescriptionLimit)
+
+	articleURL := ArticleURL(s.cfg, article.Slug)
+	breadcrumbs := []string{"articles", article.Slug}
+	if u, err := url.Parse(articleURL); err == nil && u.Host != "" {
+		breadcrumbs = append([]string{u.Host}, breadcrumbs...)
+	}
+
+	return &dto.SEOPreviewResponse{
+		Title:                title,
+		Description:          description,
+		URL:                  articleURL,
+		DisplayURL:           strings.Join(breadcrumbs, " › "),
+		Breadcrumbs:          breadcrumbs,
+		TitleTruncated:       titleTruncated,
+		DescriptionTruncated: descriptionTruncated,
+		TitleLength:          utf8.RuneCountInString(metadata.MetaTitle),
+		DescriptionLength:    utf8.RuneCountInString(metadata.MetaDescription),
+	}, nil
+}
+
+// **Score Article**
+// Uses the first SEO keyword as focus keyword unless one is given.
+func (s *seoService) ScoreArticle(articleID, requesterID uuid.UUID, role model.UserRole, keyword string) (*dto.SEOScoreResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, articleID, requesterID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := s.resolveMetadata(article)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyword == "" && len(metadata.Keywords) > 0 {
+		keyword = metadata.Keywords[0]
+	}
+
+	images, _, err := s.imageRepo.ListImagesByArticle(article.ID, 100, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var siteHosts []string
+	for _, site := range []string{s.cfg.FrontendURL, s.cfg.BaseURL} {
+		if u, err := url.Parse(site); err == nil && u.Host != "" {
+			siteHosts = append(siteHosts, u.Hostname())
+		}
+	}
+
+	score := AnalyzeSEO(SEOAnalysisInput{
+		Title:           article.Title,
+		Slug:            article.Slug,
+		Content:         article.Content,
+		MetaTitle:       metadata.MetaTitle,
+		MetaDescription: metadata.MetaDescription,
+		Keyword:         keyword,
+		Images:          images,
+		SiteHosts:       siteHosts,
+	})
+	score.ArticleID = article.ID
+
+	return &score, nil
+}
+
+// resolveMetadata fills whatever the author left empty: the title falls back
+// to the article title and the description to its first paragraph.
+func (s *seoService) resolveMetadata(article *model.Article) (*dto.SEOMetadataResponse, error) {
+	metadata, err := s.repo.GetSEOMetadataByArticle(article.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.SEOMetadataResponse{
+		ArticleID: article.ID,
+		Keywords:  []string{},
+	}
+
+	if metadata != nil {
+		response.MetaTitle = metadata.MetaTitle
+		response.MetaDescription = metadata.MetaDescription
+		if metadata.Keywords != "" {
+			response.Keywords = strings.Split(metadata.Keywords, ",")
+		}
+	}
+
+	if response.MetaTitle == "" {
+		response.MetaTitle = article.Title
+		response.TitleGenerated = true
+	}
+
+	if response.MetaDescription == "" {
+		response.MetaDescription, _ = utils.TruncateText(utils.FirstParagraph(article.Content), serpDescriptionLimit)
+		response.DescriptionGenerated = true
+	}
+
+	return response, nil
+}
+
+// ArticleURL is the public address of an article on the frontend.
+func ArticleURL(cfg *config.Config, slug string) string {
+	return strings.TrimRight(cfg.FrontendURL, "/") + "/articles/" + url.PathEscape(slug)
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+// Google truncates longer headlines in rich results.
+const jsonLDHeadlineLimit = 110
+
+// socialImage is the image shown when the article is shared.
+type socialImage struct {
+	url    string
+	alt    string
+	width  int
+	height int
+}
+
+// **Get Social Metadata**
+// Open Graph, Twitter Card and schema.org BlogPosting data for an article.
+func (s *seoService) GetSocialMetadata(articleID, requesterID uuid.UUID, role model.UserRole) (*dto.SocialMetadataResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, articleID, requesterID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := s.resolveMetadata(article)
+	if err != nil {
+		return nil, err
+	}
+
+	images, _, err := s.imageRepo.ListImagesByArticle(article.ID, 100, 0)
+	if err != nil {
+		return nil, err
+	}
+	image := s.primaryImage(article, images)
+
+	articleURL := ArticleURL(s.cfg, article.Slug)
+	published := article.CreatedAt
+	if article.PublishedAt != nil {
+		published = *article.PublishedAt
+	}
+
+	openGraph := []dto.MetaTag{
+		ogTag("og:type", "article"),
+		ogTag("og:site_name", s.cfg.SiteName),
+		ogTag("og:title", metadata.MetaTitle),
+		ogTag("og:description", metadata.MetaDescription),
+		ogTag("og:url", articleURL),
+	}
+	if image != nil {
+		openGraph = append(openGraph, ogTag("og:image", image.url))
+		if image.width > 0 && image.height > 0 {
+			openGraph = append(openGraph,
+				ogTag("og:image:width", strconv.Itoa(image.width)),
+				ogTag("og:image:height", strconv.Itoa(image.height)),
+			)
+		}
+		if image.alt != "" {
+			openGraph = append(openGraph, ogTag("og:image:alt", image.alt))
+		}
+	}
+	openGraph = append(openGraph,
+		ogTag("article:published_time", published.UTC().Format(time.RFC3339)),
+		ogTag("article:modified_time", article.UpdatedAt.UTC().Format(time.RFC3339)),
+		ogTag("article:author", article.Author.Username),
+	)
+	for _, keyword := range metadata.Keywords {
+		openGraph = append(openGraph, ogTag("article:tag", keyword))
+	}
+
+	card := "summary"
+	if image != nil {
+		card = "summary_large_image"
+	}
+	twitterCard := []dto.MetaTag{
+		twitterTag("twitter:card", card),
+		twitterTag("twitter:title", metadata.MetaTitle),
+		twitterTag("twitter:description", metadata.MetaDescription),
+	}
+	if image != nil {
+		twitterCard = append(twitterCard, twitterTag("twitter:image", image.url))
+		if image.alt != "" {
+			twitterCard = append(twitterCard, twitterTag("twitter:image:alt", image.alt))
+		}
+	}
+	if s.cfg.TwitterSite != "" {
+		twitterCard = append(twitterCard, twitterTag("twitter:site", "@"+strings.TrimPrefix(s.cfg.TwitterSite, "@")))
+	}
+
+	headline, _ := utils.TruncateText(article.Title, jsonLDHeadlineLimit)
+	publisher := map[string]any{
+		"@type": "Organization",
+		"name":  s.cfg.SiteName,
+	}
+	if s.cfg.SiteLogoURL != "" {
+		publisher["logo"] = map[string]any{"@type": "ImageObject", "url": s.cfg.SiteLogoURL}
+	}
+	jsonLD := map[string]any{
+		"@context":         "https://schema.org",
+		"@type":            "BlogPosting",
+		"headline":         headline,
+		"description":      metadata.MetaDescription,
+		"url":              articleURL,
+		"mainEntityOfPage": map[string]any{"@type": "WebPage", "@id": articleURL},
+		"datePublished":    published.UTC().Format(time.RFC3339),
+		"dateModified":     article.UpdatedAt.UTC().Format(time.RFC3339),
+		"author":           map[string]any{"@type": "Person", "name": article.Author.Username},
+		"publisher":        publisher,
+		"wordCount":        len(strings.Fields(utils.StripMarkup(article.Content))),
+	}
+	if image != nil {
+		imageObject := map[string]any{"@type": "ImageObject", "url": image.url}
+		if image.width > 0 && image.height > 0 {
+			imageObject["width"] = image.width
+			imageObject["height"] = image.height
+		}
+		jsonLD["image"] = imageObject
+	}
+	if len(metadata.Keywords) > 0 {
+		jsonLD["keywords"] = strings.Join(metadata.Keywords, ", ")
+	}
+
+	head, err := renderHead(metadata, articleURL, openGraph, twitterCard, jsonLD)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.SocialMetadataResponse{
+		ArticleID:   article.ID,
+		OpenGraph:   openGraph,
+		TwitterCard: twitterCard,
+		JSONLD:      jsonLD,
+		Head:        head,
+	}, nil
+}
+
+// primaryImage picks the first image in the content, using the uploaded
+// copy for dimensions and alt text when there is one. Without images in the
+// content the first image attached to the article is used.
+func (s *seoService) primaryImage(article *model.Article, images []model.Image) *socialImage {
+	byURL := make(map[string]model.Image, len(images))
+	for _, image := range images {
+		byURL[image.URL] = image
+	}
+
+	content := article.Content
+	var src, alt string
+	mdMatch := mdImage.FindStringSubmatchIndex(content)
+	htmlMatch := htmlImageTag.FindStringIndex(content)
+	switch {
+	case htmlMatch != nil && (mdMatch == nil || htmlMatch[0] < mdMatch[0]):
+		tag := content[htmlMatch[0]:htmlMatch[1]]
+		if m := htmlSrcAttr.FindStringSubmatch(tag); m != nil {
+			src = html.UnescapeString(m[1])
+		}
+		if m := htmlAltAttr.FindStringSubmatch(tag); m != nil {
+			alt = html.UnescapeString(m[1])
+		}
+	case mdMatch != nil:
+		alt, src = content[mdMatch[2]:mdMatch[3]], content[mdMatch[4]:mdMatch[5]]
+	}
+
+	if src != "" {
+		image := &socialImage{url: s.absoluteURL(src), alt: strings.TrimSpace(alt)}
+		if uploaded, ok := byURL[src]; ok {
+			image.width, image.height = uploaded.Width, uploaded.Height
+			if image.alt == "" {
+				image.alt = uploaded.AltText
+			}
+		}
+		return image
+	}
+
+	// images are listed newest first
+	if len(images) > 0 {
+		first := images[len(images)-1]
+		return &socialImage{
+			url:    s.absoluteURL(first.URL),
+			alt:    first.AltText,
+			width:  first.Width,
+			height: first.Height,
+		}
+	}
+	return nil
+}
+
+// absoluteURL resolves a relative image path against the API base URL,
+// since crawlers ignore relative og:image values.
+func (s *seoService) absoluteURL(ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil || u.IsAbs() {
+		return ref
+	}
+	base, err := url.Parse(s.cfg.BaseURL)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+func renderHead(metadata *dto.SEOMetadataResponse, canonicalURL string, openGraph, twitterCard []dto.MetaTag, jsonLD map[string]any) (string, error) {
+	// json.Marshal escapes <, > and &, so the data cannot close the script tag
+	data, err := json.Marshal(jsonLD)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(metadata.MetaTitle))
+	fmt.Fprintf(&b, "<meta name=\"description\" content=\"%s\">\n", html.EscapeString(metadata.MetaDescription))
+	if len(metadata.Keywords) > 0 {
+		fmt.Fprintf(&b, "<meta name=\"keywords\" content=\"%s\">\n", html.EscapeString(strings.Join(metadata.Keywords, ", ")))
+	}
+	fmt.Fprintf(&b, "<link rel=\"canonical\" href=\"%s\">\n", html.EscapeString(canonicalURL))
+	for _, tag := range append(openGraph, twitterCard...) {
+		fmt.Fprintf(&b, "<meta %s=\"%s\" content=\"%s\">\n", tag.Attribute, html.EscapeString(tag.Key), html.EscapeString(tag.Content))
+	}
+	fmt.Fprintf(&b, "<script type=\"application/ld+json\">%s</script>\n", data)
+	return b.String(), nil
+}
+
+func ogTag(property, content string) dto.MetaTag {
+	return dto.MetaTag{Attribute: "property", Key: property, Content: content}
+}
+
+func twitterTag(name, content string) dto.MetaTag {
+	return dto.MetaTag{Attribute: "name", Key: name, Content: content}
+}
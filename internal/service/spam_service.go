@@ -0,0 +1,323 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+)
+
+const (
+	// SpamReviewThreshold sends a comment to the moderation queue.
+	SpamReviewThreshold = 0.5
+	// SpamRejectThreshold marks a comment as spam right away.
+	SpamRejectThreshold = 0.9
+
+	bayesMinDocuments   = 5  // per label, before the classifier is trusted
+	bayesInterestingMax = 15 // tokens that decide a classification
+	bayesMaxTokens      = 200
+)
+
+var (
+	linkPattern  = regexp.MustCompile(`(?i)(https?://|www\.)[^\s<>"']+`)
+	tokenPattern = regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}'_-]*`)
+
+	defaultBlocklist = []string{
+		"viagra", "cialis", "casino", "slot gacor", "judi online", "togel",
+		"payday loan", "crypto giveaway", "buy followers", "seo services",
+	}
+)
+
+// SpamInput is everything the spam checks may look at.
+type SpamInput struct {
+	Content   string
+	Name      string
+	Email     string
+	Honeypot  string
+	IPAddress string
+}
+
+// SpamCheck is a single step of the spam pipeline. Score is a probability
+// in [0, 1]; a check that has nothing to say returns 0 and no reason.
+type SpamCheck interface {
+	Name() string
+	Score(input SpamInput) (float64, string, error)
+}
+
+type SpamVerdict struct {
+	Score   float64
+	Reasons []string
+}
+
+// Status maps a verdict to the status a new comment should get.
+func (v SpamVerdict) Status() model.CommentStatus {
+	switch {
+	case v.Score >= SpamRejectThreshold:
+		return model.CommentSpam
+	case v.Score >= SpamReviewThreshold:
+		return model.CommentPending
+	default:
+		return model.CommentApproved
+	}
+}
+
+type SpamService interface {
+	Evaluate(input SpamInput) SpamVerdict
+	Train(content, label string) error
+	Untrain(content, label string) error
+}
+
+type spamService struct {
+	repo   repository.SpamRepository
+	checks []SpamCheck
+}
+
+// NewSpamService runs the given checks followed by the Bayesian classifier
+// trained from moderation decisions.
+func NewSpamService(repo repository.SpamRepository, checks ...SpamCheck) SpamService {
+	return &spamService{
+		repo:   repo,
+		checks: append(checks, &bayesCheck{repo: repo}),
+	}
+}
+
+// DefaultSpamChecks returns the built-in pipeline configured from cfg.
+func DefaultSpamChecks(commentRepo repository.CommentRepository, cfg *config.Config) []SpamCheck {
+	return []SpamCheck{
+		&honeypotCheck{},
+		&linkCountCheck{},
+		&blocklistCheck{words: append(append([]string{}, defaultBlocklist...), cfg.CommentBlocklist...)},
+		&ipRateCheck{repo: commentRepo, limit: cfg.CommentRateLimit, window: cfg.CommentRateWindow},
+	}
+}
+
+// **Evaluate**
+// Check scores are combined as independent evidence (1 - Π(1 - score)), so
+// several weak signals can add up to a suspicious comment. A failing check
+// is logged and skipped rather than blocking the comment.
+func (s *spamService) Evaluate(input SpamInput) SpamVerdict {
+	var verdict SpamVerdict
+	clean := 1.0
+
+	for _, check := range s.checks {
+		score, reason, err := check.Score(input)
+		if err != nil {
+			log.Printf("Spam check %s failed: %v", check.Name(), err)
+			continue
+		}
+
+		if score <= 0 {
+			continue
+		}
+
+		clean *= 1 - math.Min(score, 1)
+		if reason != "" {
+			verdict.Reasons = append(verdict.Reasons, reason)
+		}
+	}
+
+	verdict.Score = 1 - clean
+	return verdict
+}
+
+// **Train**
+func (s *spamService) Train(content, label string) error {
+	return s.repo.AdjustTraining(spamTokens(content), label, 1)
+}
+
+// **Untrain**
+// Reverts an earlier Train call, e.g. when a moderator changes their mind.
+func (s *spamService) Untrain(content, label string) error {
+	return s.repo.AdjustTraining(spamTokens(content), label, -1)
+}
+
+// honeypotCheck flags submissions that filled the hidden form field only
+// bots can see.
+type honeypotCheck struct{}
+
+func (c *honeypotCheck) Name() string { return "honeypot" }
+
+func (c *honeypotCheck) Score(input SpamInput) (float64, string, error) {
+	if strings.TrimSpace(input.Honeypot) != "" {
+		return 1, "honeypot field was filled", nil
+	}
+	return 0, "", nil
+}
+
+type linkCountCheck struct{}
+
+func (c *linkCountCheck) Name() string { return "link_count" }
+
+func (c *linkCountCheck) Score(input SpamInput) (float64, string, error) {
+	links := len(linkPattern.FindAllString(input.Content, -1))
+
+	var score float64
+	switch {
+	case links >= 5:
+		score = 0.9
+	case links >= 3:
+		score = 0.6
+	case links == 2:
+		score = 0.3
+	default:
+		return 0, "", nil
+	}
+	return score, fmt.Sprintf("contains %d links", links), nil
+}
+
+type blocklistCheck struct {
+	words []string
+}
+
+func (c *blocklistCheck) Name() string { return "blocklist" }
+
+func (c *blocklistCheck) Score(input SpamInput) (float64, string, error) {
+	text := strings.ToLower(input.Content + " " + input.Name + " " + input.Email)
+
+	var hits []string
+	for _, word := range c.words {
+		word = strings.ToLower(word)
+		if word != "" && strings.Contains(text, word) {
+			hits = append(hits, word)
+		}
+	}
+
+	if len(hits) == 0 {
+		return 0, "", nil
+	}
+	return math.Min(0.5*float64(len(hits)), 1), "contains blocked words: " + strings.Join(hits, ", "), nil
+}
+
+// ipRateCheck flags an IP posting more than limit comments within window.
+type ipRateCheck struct {
+	repo   repository.CommentRepository
+	limit  int
+	window time.Duration
+}
+
+func (c *ipRateCheck) Name() string { return "ip_rate" }
+
+func (c *ipRateCheck) Score(input SpamInput) (float64, string, error) {
+	if input.IPAddress == "" || c.limit <= 0 {
+		return 0, "", nil
+	}
+
+	count, err := c.repo.CountRecentCommentsByIP(input.IPAddress, time.Now().Add(-c.window))
+	if err != nil {
+		return 0, "", err
+	}
+
+	if count < int64(c.limit) {
+		return 0, "", nil
+	}
+
+	score := 0.6
+	if count >= int64(2*c.limit) {
+		score = 0.95
+	}
+	return score, fmt.Sprintf("%d comments from this IP in the last %s", count, c.window), nil
+}
+
+// bayesCheck is a naive Bayes classifier over comment tokens, with Robinson's
+// smoothing for rare tokens. It stays silent until both labels have enough
+// training documents, and only reports when a comment leans towards spam.
+type bayesCheck struct {
+	repo repository.SpamRepository
+}
+
+func (c *bayesCheck) Name() string { return "bayes" }
+
+func (c *bayesCheck) Score(input SpamInput) (float64, string, error) {
+	spamDocs, hamDocs, err := c.repo.GetCorpusCounts()
+	if err != nil {
+		return 0, "", err
+	}
+
+	if spamDocs < bayesMinDocuments || hamDocs < bayesMinDocuments {
+		return 0, "", nil
+	}
+
+	tokens := spamTokens(input.Content)
+	counts, err := c.repo.GetTokenCounts(tokens)
+	if err != nil {
+		return 0, "", err
+	}
+
+	probabilities := make([]float64, 0, len(counts))
+	for _, count := range counts {
+		spamFreq := float64(count.SpamCount) / float64(spamDocs)
+		hamFreq := float64(count.HamCount) / float64(hamDocs)
+		if spamFreq+hamFreq == 0 {
+			continue
+		}
+
+		// Robinson: f(w) = (s*x + n*p(w)) / (s + n), with s = 1, x = 0.5
+		n := float64(count.SpamCount + count.HamCount)
+		p := spamFreq / (spamFreq + hamFreq)
+		f := (0.5 + n*p) / (1 + n)
+		probabilities = append(probabilities, math.Min(math.Max(f, 0.01), 0.99))
+	}
+
+	if len(probabilities) == 0 {
+		return 0, "", nil
+	}
+
+	sort.Slice(probabilities, func(i, j int) bool {
+		return math.Abs(probabilities[i]-0.5) > math.Abs(probabilities[j]-0.5)
+	})
+	if len(probabilities) > bayesInterestingMax {
+		probabilities = probabilities[:bayesInterestingMax]
+	}
+
+	var logOdds float64
+	for _, p := range probabilities {
+		logOdds += math.Log(p / (1 - p))
+	}
+	spamProbability := 1 / (1 + math.Exp(-logOdds))
+
+	if spamProbability < SpamReviewThreshold {
+		return 0, "", nil
+	}
+	return spamProbability, fmt.Sprintf("classifier spam probability %.2f", spamProbability), nil
+}
+
+// spamTokens splits content into the unique lowercase words and link hosts
+// the classifier learns from.
+func spamTokens(content string) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+
+	add := func(token string) {
+		if len(token) <= 64 && !seen[token] && len(tokens) < bayesMaxTokens {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, link := range linkPattern.FindAllString(content, -1) {
+		if !strings.Contains(link, "://") {
+			link = "http://" + link
+		}
+		if u, err := url.Parse(link); err == nil && u.Hostname() != "" {
+			add("host:" + strings.ToLower(u.Hostname()))
+		}
+	}
+
+	for _, word := range tokenPattern.FindAllString(strings.ToLower(content), -1) {
+		word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsNumber(r) })
+		if length := len(word); length >= 3 && length <= 32 {
+			add(word)
+		}
+	}
+
+	return tokens
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+)
+
+// memorySpamRepository keeps the classifier counts in memory; counts never
+// drop below zero, as in the database repository.
+type memorySpamRepository struct {
+	tokens    map[string]model.SpamToken
+	documents map[string]int
+}
+
+func newMemorySpamRepository() *memorySpamRepository {
+	return &memorySpamRepository{
+		tokens:    make(map[string]model.SpamToken),
+		documents: make(map[string]int),
+	}
+}
+
+func (r *memorySpamRepository) GetTokenCounts(tokens []string) (map[string]model.SpamToken, error) {
+	counts := make(map[string]model.SpamToken)
+	for _, token := range tokens {
+		if row, ok := r.tokens[token]; ok {
+			counts[token] = row
+		}
+	}
+	return counts, nil
+}
+
+func (r *memorySpamRepository) GetCorpusCounts() (int, int, error) {
+	return r.documents[model.SpamLabel], r.documents[model.HamLabel], nil
+}
+
+func (r *memorySpamRepository) AdjustTraining(tokens []string, label string, delta int) error {
+	for _, token := range tokens {
+		row := r.tokens[token]
+		row.Token = token
+		if label == model.SpamLabel {
+			row.SpamCount = max(row.SpamCount+delta, 0)
+		} else {
+			row.HamCount = max(row.HamCount+delta, 0)
+		}
+		r.tokens[token] = row
+	}
+	r.documents[label] = max(r.documents[label]+delta, 0)
+	return nil
+}
+
+// recentCommentRepository answers CountRecentCommentsByIP with a fixed count.
+type recentCommentRepository struct {
+	repository.CommentRepository
+	count int64
+	err   error
+	since time.Time
+}
+
+func (r *recentCommentRepository) CountRecentCommentsByIP(ip string, since time.Time) (int64, error) {
+	r.since = since
+	return r.count, r.err
+}
+
+type staticSpamCheck struct {
+	score  float64
+	reason string
+	err    error
+}
+
+func (c staticSpamCheck) Name() string { return "static" }
+
+func (c staticSpamCheck) Score(SpamInput) (float64, string, error) {
+	return c.score, c.reason, c.err
+}
+
+func TestLinkCountCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    float64
+	}{
+		{"no links", "Nice article, thanks.", 0},
+		{"one link", "See https://go.dev for more.", 0},
+		{"two links", "https://a.example and www.b.example", 0.3},
+		{"three links", "http://a.example http://b.example http://c.example", 0.6},
+		{"four links", strings.Repeat("https://x.example/p ", 4), 0.6},
+		{"five links", strings.Repeat("www.x.example ", 5), 0.9},
+	}
+
+	check := &linkCountCheck{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reason, err := check.Score(SpamInput{Content: tt.content})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if score != tt.want {
+				t.Errorf("score = %v, want %v", score, tt.want)
+			}
+			if (reason != "") != (tt.want > 0) {
+				t.Errorf("reason = %q with score %v", reason, score)
+			}
+		})
+	}
+}
+
+func TestBlocklistCheck(t *testing.T) {
+	cfg := &config.Config{CommentBlocklist: []string{"Cheap Pills", ""}}
+	var check SpamCheck
+	for _, c := range DefaultSpamChecks(&recentCommentRepository{}, cfg) {
+		if c.Name() == "blocklist" {
+			check = c
+		}
+	}
+	if check == nil {
+		t.Fatal("blocklist check missing from the default pipeline")
+	}
+
+	tests := []struct {
+		name  string
+		input SpamInput
+		want  float64
+	}{
+		{"clean", SpamInput{Content: "A thoughtful reply.", Name: "Ana", Email: "ana@example.com"}, 0},
+		{"one word, any case", SpamInput{Content: "Best CASINO in town"}, 0.5},
+		{"phrase", SpamInput{Content: "try slot gacor today"}, 0.5},
+		{"configured word", SpamInput{Content: "cheap pills here"}, 0.5},
+		{"name and email count", SpamInput{Content: "hello", Name: "Viagra Shop", Email: "togel@example.com"}, 1},
+		{"capped at one", SpamInput{Content: "viagra cialis casino togel"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reason, err := check.Score(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if score != tt.want {
+				t.Errorf("score = %v (%s), want %v", score, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPRateCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		ip      string
+		limit   int
+		count   int64
+		err     error
+		want    float64
+		wantErr bool
+	}{
+		{"no ip", "", 3, 10, nil, 0, false},
+		{"disabled", "203.0.113.7", 0, 10, nil, 0, false},
+		{"below limit", "203.0.113.7", 3, 2, nil, 0, false},
+		{"at limit", "203.0.113.7", 3, 3, nil, 0.6, false},
+		{"just below twice the limit", "203.0.113.7", 3, 5, nil, 0.6, false},
+		{"twice the limit", "203.0.113.7", 3, 6, nil, 0.95, false},
+		{"lookup fails", "203.0.113.7", 3, 0, errors.New("db down"), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &recentCommentRepository{count: tt.count, err: tt.err}
+			check := &ipRateCheck{repo: repo, limit: tt.limit, window: 10 * time.Minute}
+
+			score, _, err := check.Score(SpamInput{IPAddress: tt.ip})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if score != tt.want {
+				t.Errorf("score = %v, want %v", score, tt.want)
+			}
+			if !repo.since.IsZero() {
+				if ago := time.Since(repo.since); ago < 10*time.Minute || ago > 11*time.Minute {
+					t.Errorf("counted comments since %v ago, want the 10m window", ago)
+				}
+			}
+		})
+	}
+}
+
+func TestSpamServiceEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []SpamCheck
+		wantScore  float64
+		wantStatus model.CommentStatus
+	}{
+		{"no signals", nil, 0, model.CommentApproved},
+		{"weak signal", []SpamCheck{staticSpamCheck{score: 0.3, reason: "a"}}, 0.3, model.CommentApproved},
+		{"weak signals add up", []SpamCheck{staticSpamCheck{score: 0.5, reason: "a"}, staticSpamCheck{score: 0.5, reason: "b"}}, 0.75, model.CommentPending},
+		{"certain spam", []SpamCheck{staticSpamCheck{score: 1, reason: "a"}, staticSpamCheck{score: 0.3, reason: "b"}}, 1, model.CommentSpam},
+		{"failing check is skipped", []SpamCheck{staticSpamCheck{score: 1, err: errors.New("boom")}, staticSpamCheck{score: 0.6, reason: "a"}}, 0.6, model.CommentPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := NewSpamService(newMemorySpamRepository(), tt.checks...).Evaluate(SpamInput{Content: "hello"})
+			if math.Abs(verdict.Score-tt.wantScore) > 1e-9 {
+				t.Errorf("Score = %v, want %v", verdict.Score, tt.wantScore)
+			}
+			if got := verdict.Status(); got != tt.wantStatus {
+				t.Errorf("Status() = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestBayesClassifier(t *testing.T) {
+	spam := []string{
+		"Win free money now at the best casino bonus",
+		"Free bonus money, claim your casino prize now",
+		"Claim free money with this bonus offer now",
+		"Cheap bonus offer, win money fast, visit now",
+		"Casino prize money waiting, claim the free bonus",
+	}
+	ham := []string{
+		"Great article about testing goroutines in golang",
+		"Thanks, the section about channels cleared things up",
+		"I tried the golang example and the tests pass",
+		"Could you write more about channels and context",
+		"Clear explanation of goroutines, thanks for the article",
+	}
+
+	repo := newMemorySpamRepository()
+	service := NewSpamService(repo)
+	classify := func(content string) float64 {
+		return service.Evaluate(SpamInput{Content: content}).Score
+	}
+
+	for _, content := range spam[:4] {
+		if err := service.Train(content, model.SpamLabel); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, content := range ham {
+		if err := service.Train(content, model.HamLabel); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if score := classify("free casino bonus money now"); score != 0 {
+		t.Errorf("score with too few spam documents = %v, want 0", score)
+	}
+
+	if err := service.Train(spam[4], model.SpamLabel); err != nil {
+		t.Fatal(err)
+	}
+
+	if score := classify("Claim your free casino bonus money now"); score < SpamRejectThreshold {
+		t.Errorf("spam-like comment scored %v, want at least %v", score, SpamRejectThreshold)
+	}
+	if score := classify("More about goroutines and channels in golang, thanks"); score != 0 {
+		t.Errorf("ham-like comment scored %v, want 0", score)
+	}
+	if score := classify("Lorem ipsum dolor sit amet"); score != 0 {
+		t.Errorf("comment of unseen words scored %v, want 0", score)
+	}
+
+	if err := service.Untrain(spam[4], model.SpamLabel); err != nil {
+		t.Fatal(err)
+	}
+	if score := classify("Claim your free casino bonus money now"); score != 0 {
+		t.Errorf("score after untraining below the minimum = %v, want 0", score)
+	}
+}
+
+func TestSpamTokens(t *testing.T) {
+	got := spamTokens("Visit https://Spam.example/x and www.other.example today. It's GREAT, great... ok")
+	want := []string{"host:spam.example", "host:www.other.example", "visit", "https", "spam", "example", "and", "www", "other", "today", "it's", "great"}
+
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("spamTokens() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+// Tag names are compared through a normalized key so that spelling variants
+// of the same tag ("Go", "golang", "go-lang", "#Golang") are treated as one.
+//
+// The key is built in three steps:
+//  1. "+" and "#" are spelled out, so "C", "C++" and "C#" stay distinct.
+//  2. The name is slugified and the hyphens are dropped, which removes case,
+//     accents, spacing and punctuation differences.
+//  3. Well-known aliases are mapped to their canonical key.
+
+// minFuzzyTagKeyLength is the key length from which a single typo counts as
+// the same tag; shorter keys are too easy to collide ("java" and "lava").
+const minFuzzyTagKeyLength = 6
+
+var tagKeySymbols = strings.NewReplacer("+", " plus ", "#", " sharp ")
+
+// tagAliases maps alternative spellings to the key of the preferred name.
+var tagAliases = map[string]string{
+	"golang":     "go",
+	"js":         "javascript",
+	"ecmascript": "javascript",
+	"ts":         "typescript",
+	"py":         "python",
+	"k8s":        "kubernetes",
+	"postgres":   "postgresql",
+	"psql":       "postgresql",
+	"mongo":      "mongodb",
+	"reactjs":    "react",
+	"vuejs":      "vue",
+	"node":       "nodejs",
+	"ml":         "machinelearning",
+	"ai":         "artificialintelligence",
+}
+
+// cleanTagName tidies a tag name for display: hashtag prefixes and extra
+// whitespace are removed, the author's casing is kept.
+func cleanTagName(name string) string {
+	return utils.CollapseWhitespace(strings.TrimLeft(strings.TrimSpace(name), "#"))
+}
+
+// tagKey returns the normalized key used to detect duplicate tags.
+func tagKey(name string) string {
+	key := tagSpelling(name)
+	if alias, ok := tagAliases[key]; ok {
+		return alias
+	}
+	return key
+}
+
+// tagSpelling is the key before aliases are applied, which is what a
+// partially typed name has to be compared with.
+func tagSpelling(name string) string {
+	return strings.ReplaceAll(utils.Slugify(tagKeySymbols.Replace(cleanTagName(name))), "-", "")
+}
+
+// isNearDuplicateKey reports whether two tag keys probably mean the same
+// tag: they are equal, or long enough that one edit apart is likely a typo
+// or plural. It only drives hints, never matching. Keys whose numbers
+// differ ("python2", "python3") are always distinct.
+func isNearDuplicateKey(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if min(len(a), len(b)) < minFuzzyTagKeyLength || digitsOf(a) != digitsOf(b) {
+		return false
+	}
+	return utils.Levenshtein(a, b) <= 1
+}
+
+func digitsOf(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, text)
+}
+
+// oneEditPatterns returns LIKE patterns matching every string one insertion,
+// substitution or deletion away from key, each followed by suffix. Keys only
+// hold [a-z0-9], so they need no escaping.
+func oneEditPatterns(key, suffix string) []string {
+	seen := make(map[string]bool)
+	var patterns []string
+	add := func(pattern string) {
+		if pattern != "" && !seen[pattern] {
+			seen[pattern] = true
+			patterns = append(patterns, pattern+suffix)
+		}
+	}
+
+	for i := 0; i <= len(key); i++ {
+		add(key[:i] + "_" + key[i:])
+		if i < len(key) {
+			add(key[:i] + "_" + key[i+1:])
+			add(key[:i] + key[i+1:])
+		}
+	}
+	return patterns
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestTagKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Go", "go"},
+		{"golang", "go"},
+		{"#Golang", "go"},
+		{"go-lang", "go"},
+		{"  Machine   Learning ", "machinelearning"},
+		{"ML", "machinelearning"},
+		{"k8s", "kubernetes"},
+		{"Postgres", "postgresql"},
+		{"C", "c"},
+		{"C++", "cplusplus"},
+		{"C#", "csharp"},
+		{"Node.js", "nodejs"},
+		{"Café", "cafe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagKey(tt.name); got != tt.want {
+				t.Errorf("tagKey(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsNearDuplicateKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"go", "go", true},
+		{"kubernetes", "kubernets", true},
+		{"javascript", "javascripts", true},
+		{"postgresql", "postgrsql", true},
+		{"java", "lava", false},
+		{"react", "reacts", false},
+		{"python2", "python3", false},
+		{"python3", "pythons3", true},
+		{"html5", "html", false},
+		{"kubernetes", "kubrnets", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := isNearDuplicateKey(tt.a, tt.b); got != tt.want {
+				t.Errorf("isNearDuplicateKey(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOneEditPatterns(t *testing.T) {
+	tests := []struct {
+		candidate string
+		want      bool
+	}{
+		{"kubernetes", true}, // callers drop the key itself
+		{"kubernets", true},
+		{"kubernetess", true},
+		{"kubarnetes", true},
+		{"xkubernetes", true},
+		{"kubrnets", false},
+		{"kubernetesio", false},
+	}
+
+	var matchers []*regexp.Regexp
+	for _, pattern := range oneEditPatterns("kubernetes", "") {
+		expr := strings.NewReplacer("_", ".", "%", ".*").Replace(pattern)
+		matchers = append(matchers, regexp.MustCompile("^"+expr+"$"))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.candidate, func(t *testing.T) {
+			got := false
+			for _, matcher := range matchers {
+				if matcher.MatchString(tt.candidate) {
+					got = true
+				}
+			}
+			if got != tt.want {
+				t.Errorf("patterns match %q = %v, want %v", tt.candidate, got, tt.want)
+			}
+		})
+	}
+
+	if got := oneEditPatterns("go", "%"); len(got) != 7 {
+		t.Errorf("oneEditPatterns(go) = %v, want 7 distinct patterns", got)
+	}
+}
@@ -1 +1,619 @@
 package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/constant"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+type TagService interface {
+	ListTags() ([]dto.TagResponse, error)
+	AutocompleteTags(query string, limit int) ([]dto.TagResponse, error)
+	GetTagByID(id uuid.UUID) (*dto.TagResponse, error)
+	GetTagBySlug(slug string) (*dto.TagResponse, error)
+	CreateTag(req dto.TagRequest) (*dto.TagResponse, []dto.TagNearMatch, error)
+	UpdateTag(id uuid.UUID, req dto.TagRequest) (*dto.TagResponse, error)
+	DeleteTag(id uuid.UUID) error
+	MergeTags(targetID uuid.UUID, sourceIDs []uuid.UUID) (*dto.TagResponse, error)
+	BackfillTagKeys() error
+
+	GetArticleTags(articleID, requesterID uuid.UUID, role model.UserRole) ([]dto.TagResponse, error)
+	SetArticleTags(articleID, userID uuid.UUID, role model.UserRole, req dto.ArticleTagsRequest) ([]dto.TagResponse, []dto.TagNearMatch, error)
+	SuggestArticleTags(articleID, userID uuid.UUID, role model.UserRole, limit int) ([]dto.TagSuggestionResponse, error)
+}
+
+const (
+	// autocompleteCandidates is how many matching tags are read per query
+	// before they are ranked, leaving room to favour the most used ones.
+	autocompleteCandidates = 50
+	// nearTagLimit caps the tags read when looking for near matches.
+	nearTagLimit = 20
+)
+
+type tagService struct {
+	repo           repository.TagRepository
+	articleTagRepo repository.ArticleTagRepository
+	articleRepo    repository.ArticleRepository
+}
+
+func NewTagService(repo repository.TagRepository, articleTagRepo repository.ArticleTagRepository, articleRepo repository.ArticleRepository) TagService {
+	return &tagService{
+		repo:           repo,
+		articleTagRepo: articleTagRepo,
+		articleRepo:    articleRepo,
+	}
+}
+
+// **List Tags**
+func (s *tagService) ListTags() ([]dto.TagResponse, error) {
+	tags, err := s.repo.ListTags()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponses(tags)
+}
+
+// **Autocomplete Tags**
+// Exact matches come first, then prefix, word prefix, substring and
+// one-typo matches; ties go to the tag used by more articles.
+func (s *tagService) AutocompleteTags(query string, limit int) ([]dto.TagResponse, error) {
+	spelling := tagSpelling(query)
+	if spelling == "" {
+		return []dto.TagResponse{}, nil
+	}
+	if limit < 1 || limit > 25 {
+		limit = 10
+	}
+
+	key := tagKey(query)
+	ranks := make(map[uuid.UUID]int)
+	var matches []model.Tag
+	collect := func(patterns []string) error {
+		tags, err := s.repo.ListTagsByNameKeyPatterns(patterns, autocompleteCandidates)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if _, seen := ranks[tag.ID]; seen {
+				continue
+			}
+			if rank, ok := autocompleteRank(spelling, key, tag.Name); ok {
+				ranks[tag.ID] = rank
+				matches = append(matches, tag)
+			}
+		}
+		return nil
+	}
+
+	// prefix matches can use the index, the slower substring and typo
+	// matches are only looked up when those do not fill the list
+	prefixes := append([]string{spelling + "%", key}, aliasTargetsWithPrefix(spelling)...)
+	if err := collect(prefixes); err != nil {
+		return nil, err
+	}
+	if len(matches) < limit {
+		patterns := []string{"%" + spelling + "%"}
+		if len(spelling) >= 3 {
+			patterns = append(patterns, oneEditPatterns(spelling, "%")...)
+		}
+		if err := collect(patterns); err != nil {
+			return nil, err
+		}
+	}
+
+	responses, err := s.toResponses(matches)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(responses, func(i, j int) bool {
+		a, b := responses[i], responses[j]
+		if ranks[a.ID] != ranks[b.ID] {
+			return ranks[a.ID] < ranks[b.ID]
+		}
+		if a.ArticleCount != b.ArticleCount {
+			return a.ArticleCount > b.ArticleCount
+		}
+		return a.Name < b.Name
+	})
+	if len(responses) > limit {
+		responses = responses[:limit]
+	}
+	return responses, nil
+}
+
+// **Get Tag By ID**
+func (s *tagService) GetTagByID(id uuid.UUID) (*dto.TagResponse, error) {
+	tag, err := s.repo.GetTagByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(tag)
+}
+
+// **Get Tag By Slug**
+func (s *tagService) GetTagBySlug(slug string) (*dto.TagResponse, error) {
+	tag, err := s.repo.GetTagBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(tag)
+}
+
+// **Create Tag**
+// Tags one typo away do not block the new tag; they come back as near
+// matches for the client to point out.
+func (s *tagService) CreateTag(req dto.TagRequest) (*dto.TagResponse, []dto.TagNearMatch, error) {
+	tag := &model.Tag{Name: cleanTagName(req.Name)}
+
+	key, err := s.ensureNoDuplicate(tag.Name, uuid.Nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	tag.NameKey = key
+
+	slug, err := s.resolveSlug(req.Slug, tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	tag.Slug = slug
+
+	if err := s.repo.CreateTag(tag); err != nil {
+		if isTagConflict(err) {
+			return nil, nil, err
+		}
+		log.Println("Error creating tag:", err)
+		return nil, nil, errors.New("Failed to create tag")
+	}
+
+	response, err := s.toResponse(tag)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names, err := s.nearTagNames(key, tag.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	nearMatches := []dto.TagNearMatch{}
+	if len(names) > 0 {
+		nearMatches = append(nearMatches, dto.TagNearMatch{Name: tag.Name, DidYouMean: names})
+	}
+	return response, nearMatches, nil
+}
+
+// **Update Tag**
+// The slug only changes when one is sent, so existing links keep working.
+func (s *tagService) UpdateTag(id uuid.UUID, req dto.TagRequest) (*dto.TagResponse, error) {
+	tag, err := s.repo.GetTagByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, model.ErrTagNotFound
+	}
+
+	tag.Name = cleanTagName(req.Name)
+	if tag.NameKey, err = s.ensureNoDuplicate(tag.Name, tag.ID); err != nil {
+		return nil, err
+	}
+
+	if req.Slug != "" || tag.Slug == "" {
+		if tag.Slug, err = s.resolveSlug(req.Slug, tag); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateTag(tag); err != nil {
+		if isTagConflict(err) {
+			return nil, err
+		}
+		log.Println("Error updating tag:", err)
+		return nil, errors.New("Failed to update tag")
+	}
+
+	return s.toResponse(tag)
+}
+
+// **Delete Tag**
+func (s *tagService) DeleteTag(id uuid.UUID) error {
+	tag, err := s.repo.GetTagByID(id)
+	if err != nil {
+		return err
+	}
+	if tag == nil {
+		return model.ErrTagNotFound
+	}
+
+	if err := s.repo.DeleteTag(id); err != nil {
+		log.Println("Error deleting tag:", err)
+		return errors.New("Failed to delete tag")
+	}
+	return nil
+}
+
+// **Merge Tags**
+// Moves the articles of the source tags to the target tag, which survives,
+// and deletes the source tags.
+func (s *tagService) MergeTags(targetID uuid.UUID, sourceIDs []uuid.UUID) (*dto.TagResponse, error) {
+	target, err := s.repo.GetTagByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, model.ErrTagNotFound
+	}
+
+	sourceIDs = uniqueIDs(sourceIDs)
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return nil, model.ErrTagMergeItself
+		}
+	}
+
+	sources, err := s.repo.GetTagsByIDs(sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) != len(sourceIDs) {
+		return nil, model.ErrTagNotFound
+	}
+
+	if err := s.articleTagRepo.MergeTags(targetID, sourceIDs); err != nil {
+		log.Println("Error merging tags:", err)
+		return nil, errors.New("Failed to merge tags")
+	}
+
+	return s.toResponse(target)
+}
+
+// **Backfill Tag Keys**
+// Stores the normalized key of tags created before keys were kept in the
+// database. A tag whose key another tag already holds keeps none and is
+// logged so it can be merged.
+func (s *tagService) BackfillTagKeys() error {
+	tags, err := s.repo.ListTagsWithoutNameKey()
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		key := tagKey(tag.Name)
+		if key == "" {
+			log.Println("Tag name has no usable key:", tag.Name)
+			continue
+		}
+		if err := s.repo.SetTagNameKey(tag.ID, key); err != nil {
+			if errors.Is(err, model.ErrTagNameExists) {
+				log.Printf("Tag %q (%s) duplicates another tag and should be merged\n", tag.Name, tag.ID)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// **Get Article Tags**
+func (s *tagService) GetArticleTags(articleID, requesterID uuid.UUID, role model.UserRole) ([]dto.TagResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, articleID, requesterID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.articleTagRepo.ListTagsByArticle(article.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponses(tags)
+}
+
+// **Set Article Tags**
+// Replaces the article's tags with the given ones. Names that match no
+// existing tag create one for roles that manage tags; tags one typo away
+// from such a name come back as near matches.
+func (s *tagService) SetArticleTags(articleID, userID uuid.UUID, role model.UserRole, req dto.ArticleTagsRequest) ([]dto.TagResponse, []dto.TagNearMatch, error) {
+	article, err := findOwnedArticle(s.articleRepo, articleID, userID, role)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	namedIDs, nearMatches, err := s.resolveTagNames(req.TagNames, role)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tagIDs := uniqueIDs(append(req.TagIDs, namedIDs...))
+	tags, err := s.repo.GetTagsByIDs(tagIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tags) != len(tagIDs) {
+		return nil, nil, model.ErrTagNotFound
+	}
+
+	if err := s.articleTagRepo.ReplaceArticleTags(article.ID, tagIDs); err != nil {
+		log.Println("Error saving article tags:", err)
+		return nil, nil, errors.New("Failed to save article tags")
+	}
+
+	responses, err := s.toResponses(tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	return responses, nearMatches, nil
+}
+
+// **Suggest Article Tags**
+// Proposes tags for the article from the content of published articles
+// that are already tagged.
+func (s *tagService) SuggestArticleTags(articleID, userID uuid.UUID, role model.UserRole, limit int) ([]dto.TagSuggestionResponse, error) {
+	article, err := findOwnedArticle(s.articleRepo, articleID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 || limit > 20 {
+		limit = 5
+	}
+
+	corpus, err := s.articleTagRepo.ListTaggedArticles(article.ID, suggestionCorpusSize)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.repo.ListTags()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.articleTagRepo.ListTagsByArticle(article.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []dto.TagSuggestionResponse{}
+	for _, suggestion := range suggestTags(article, corpus, candidates, current, limit) {
+		terms := suggestion.matchedTerms
+		if terms == nil {
+			terms = []string{}
+		}
+		responses = append(responses, dto.TagSuggestionResponse{
+			ID:           suggestion.tag.ID,
+			Name:         suggestion.tag.Name,
+			Slug:         suggestion.tag.Slug,
+			Score:        suggestion.score,
+			MatchedTerms: terms,
+		})
+	}
+	return responses, nil
+}
+
+// ensureNoDuplicate rejects a name that normalizes to the same key as
+// another tag, and returns the key. The unique index on the key stops the
+// writes that race past this check.
+func (s *tagService) ensureNoDuplicate(name string, tagID uuid.UUID) (string, error) {
+	key := tagKey(name)
+	if key == "" {
+		return "", model.ErrTagNameInvalid
+	}
+
+	existing, err := s.repo.GetTagByNameKey(key)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil && existing.ID != tagID {
+		return "", fmt.Errorf("%w: %s", model.ErrTagNameExists, existing.Name)
+	}
+	return key, nil
+}
+
+// resolveTagNames maps tag names to existing tags by their normalized key,
+// aliases included. A near match is never used in place of the name, it is
+// only reported as a hint.
+func (s *tagService) resolveTagNames(names []string, role model.UserRole) ([]uuid.UUID, []dto.TagNearMatch, error) {
+	nearMatches := []dto.TagNearMatch{}
+	ids := make([]uuid.UUID, 0, len(names))
+	for _, name := range names {
+		key := tagKey(name)
+		if key == "" {
+			return nil, nil, model.ErrTagNameInvalid
+		}
+
+		tag, err := s.repo.GetTagByNameKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if tag != nil {
+			ids = append(ids, tag.ID)
+			continue
+		}
+
+		near, err := s.nearTagNames(key, uuid.Nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !constant.HasPermission(role, constant.PermTagManage) {
+			if len(near) > 0 {
+				return nil, nil, fmt.Errorf("%w: %s (did you mean %s?)", model.ErrTagNotFound, cleanTagName(name), strings.Join(near, ", "))
+			}
+			return nil, nil, fmt.Errorf("%w: %s", model.ErrTagNotFound, cleanTagName(name))
+		}
+
+		tag = &model.Tag{Name: cleanTagName(name), NameKey: key}
+		if tag.Slug, err = s.resolveSlug("", tag); err != nil {
+			return nil, nil, err
+		}
+		if err := s.repo.CreateTag(tag); err != nil {
+			// another request may have created the same tag in the meantime
+			if errors.Is(err, model.ErrTagNameExists) {
+				if existing, findErr := s.repo.GetTagByNameKey(key); findErr == nil && existing != nil {
+					ids = append(ids, existing.ID)
+					continue
+				}
+			}
+			if isTagConflict(err) {
+				return nil, nil, err
+			}
+			log.Println("Error creating tag:", err)
+			return nil, nil, errors.New("Failed to create tag")
+		}
+
+		if len(near) > 0 {
+			nearMatches = append(nearMatches, dto.TagNearMatch{Name: tag.Name, DidYouMean: near})
+		}
+		ids = append(ids, tag.ID)
+	}
+	return ids, nearMatches, nil
+}
+
+// nearTagNames lists the tags one typo away from key, by name, leaving out
+// the tag with tagID.
+func (s *tagService) nearTagNames(key string, tagID uuid.UUID) ([]string, error) {
+	if len(key) < minFuzzyTagKeyLength {
+		return nil, nil
+	}
+
+	tags, err := s.repo.ListTagsByNameKeyPatterns(oneEditPatterns(key, ""), nearTagLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, tag := range tags {
+		if tag.ID != tagID && tag.NameKey != key && isNearDuplicateKey(key, tag.NameKey) {
+			names = append(names, tag.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// isTagConflict reports whether err says the tag name or slug is taken.
+func isTagConflict(err error) bool {
+	return errors.Is(err, model.ErrTagNameExists) || errors.Is(err, model.ErrTagSlugExists)
+}
+
+// autocompleteRank places a tag in the autocomplete order, lower first, and
+// reports whether it matches at all.
+func autocompleteRank(spelling, key, name string) (int, bool) {
+	tagSpelt := tagSpelling(name)
+	tagKeyed := tagKey(name)
+
+	switch {
+	case key == tagKeyed:
+		return 0, true
+	case strings.HasPrefix(tagSpelt, spelling) || aliasHasPrefix(spelling, tagKeyed):
+		return 1, true
+	}
+
+	for _, word := range strings.Split(utils.Slugify(name), "-") {
+		if strings.HasPrefix(word, spelling) {
+			return 2, true
+		}
+	}
+
+	if strings.Contains(tagSpelt, spelling) {
+		return 3, true
+	}
+
+	// one typo in the typed prefix
+	if len(spelling) >= 3 {
+		prefix := tagSpelt[:min(len(tagSpelt), len(spelling))]
+		if utils.Levenshtein(spelling, prefix) <= 1 {
+			return 4, true
+		}
+	}
+	return 0, false
+}
+
+// aliasHasPrefix reports whether an alias of the tag starts with the typed
+// text, so "gola" finds the "Go" tag through "golang".
+func aliasHasPrefix(spelling, key string) bool {
+	for alias, target := range tagAliases {
+		if target == key && strings.HasPrefix(alias, spelling) {
+			return true
+		}
+	}
+	return false
+}
+
+// aliasTargetsWithPrefix returns the keys of the tags that have an alias
+// starting with the typed text.
+func aliasTargetsWithPrefix(spelling string) []string {
+	var keys []string
+	for alias, target := range tagAliases {
+		if strings.HasPrefix(alias, spelling) {
+			keys = append(keys, target)
+		}
+	}
+	return keys
+}
+
+// resolveSlug validates a slug chosen by the caller, or derives a free one
+// from the name when none was given.
+func (s *tagService) resolveSlug(requested string, tag *model.Tag) (string, error) {
+	if slug := utils.Slugify(requested); slug != "" {
+		existing, err := s.repo.GetTagBySlug(slug)
+		if err != nil {
+			return "", err
+		}
+		if existing != nil && existing.ID != tag.ID {
+			return "", model.ErrTagSlugExists
+		}
+		return slug, nil
+	}
+
+	base := utils.Slugify(tag.Name)
+	if base == "" {
+		base = "tag"
+	}
+	existing, err := s.repo.ListSlugsWithPrefix(base)
+	if err != nil {
+		return "", err
+	}
+	return utils.UniqueSlug(base, tag.Slug, existing), nil
+}
+
+func (s *tagService) toResponse(tag *model.Tag) (*dto.TagResponse, error) {
+	if tag == nil {
+		return nil, model.ErrTagNotFound
+	}
+
+	responses, err := s.toResponses([]model.Tag{*tag})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+func (s *tagService) toResponses(tags []model.Tag) ([]dto.TagResponse, error) {
+	counts, err := s.articleTagRepo.CountPublishedArticlesPerTag()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		responses = append(responses, dto.TagResponse{
+			ID:           tag.ID,
+			Name:         tag.Name,
+			Slug:         tag.Slug,
+			ArticleCount: counts[tag.ID],
+			CreatedAt:    tag.CreatedAt,
+			UpdatedAt:    tag.UpdatedAt,
+		})
+	}
+	return responses, nil
+}
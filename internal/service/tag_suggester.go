@@ -0,0 +1,240 @@
+package service
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
+)
+
+// Tag suggestions compare an article with the published articles that
+// already carry tags. Every document becomes a TF-IDF vector; each tag is
+// represented by the centroid of its articles' vectors and scored by cosine
+// similarity with the article. Tags whose name appears in the article's own
+// text get a fixed boost, so tags without articles can still be suggested.
+const (
+	suggestionCorpusSize  = 500
+	minSuggestionScore    = 0.05
+	tagNameMatchBoost     = 0.2
+	suggestionTitleWeight = 2
+	matchedTermsPerTag    = 3
+)
+
+var suggestionToken = regexp.MustCompile(`[\p{L}\p{N}]+(?:[.+#-][\p{L}\p{N}]+)*[+#]*`)
+
+// suggestionStopwords are skipped when building term vectors. The content
+// is written in both English and Indonesian.
+var suggestionStopwords = toSet(strings.Fields(`
+	about after again all also and any are because been before being between both
+	but can could did does doing down during each few for from further had has have
+	having her here hers him his how into its just more most not now off once only
+	other our ours out over own same she should some such than that the their theirs
+	them then there these they this those through too under until very was were what
+	when where which while who whom why will with would you your yours
+	ada adalah agar akan aku anda antara apa apabila atau bagaimana bagi bahwa banyak
+	belum bisa buat bukan dalam dan dapat dari daripada dengan di dia hal hanya harus
+	ini itu jadi jika juga kalau kami kamu karena ke kita lagi lain lebih maka masih
+	mereka merupakan namun oleh pada para saat saja sangat satu saya secara sebagai
+	sedang sehingga sejak seperti serta setelah sudah supaya tanpa tapi telah tentang
+	tersebut tetapi untuk waktu yaitu yakni yang
+`))
+
+type tagSuggestion struct {
+	tag          model.Tag
+	score        float64
+	matchedTerms []string
+}
+
+type termVector map[string]float64
+
+// suggestTags ranks candidate tags for the article. Tags the article
+// already carries are left out.
+func suggestTags(article *model.Article, corpus []model.Article, candidates []model.Tag, current []model.Tag, limit int) []tagSuggestion {
+	target := termCounts(article.Title, article.Content)
+	if len(target) == 0 {
+		return nil
+	}
+
+	documents := make([]map[string]int, len(corpus))
+	documentFrequency := make(map[string]int)
+	for term := range target {
+		documentFrequency[term]++
+	}
+	for i, doc := range corpus {
+		documents[i] = termCounts(doc.Title, doc.Content)
+		for term := range documents[i] {
+			documentFrequency[term]++
+		}
+	}
+
+	total := float64(len(corpus) + 1)
+	weigh := func(counts map[string]int) termVector {
+		vector := make(termVector, len(counts))
+		for term, count := range counts {
+			idf := math.Log((total+1)/float64(documentFrequency[term]+1)) + 1
+			vector[term] = (1 + math.Log(float64(count))) * idf
+		}
+		return vector.normalized()
+	}
+
+	centroids := make(map[uuid.UUID]termVector)
+	for i, doc := range corpus {
+		vector := weigh(documents[i])
+		for _, tag := range doc.Tags {
+			centroid := centroids[tag.ID]
+			if centroid == nil {
+				centroid = make(termVector)
+				centroids[tag.ID] = centroid
+			}
+			for term, weight := range vector {
+				centroid[term] += weight
+			}
+		}
+	}
+
+	targetVector := weigh(target)
+	mentioned := mentionedTagKeys(article.Title, article.Content)
+
+	skip := make(map[uuid.UUID]bool, len(current))
+	for _, tag := range current {
+		skip[tag.ID] = true
+	}
+
+	var suggestions []tagSuggestion
+	for _, tag := range candidates {
+		if skip[tag.ID] {
+			continue
+		}
+
+		suggestion := tagSuggestion{tag: tag}
+		if centroid, ok := centroids[tag.ID]; ok {
+			centroid = centroid.normalized()
+			suggestion.score = targetVector.dot(centroid)
+			suggestion.matchedTerms = topSharedTerms(targetVector, centroid, matchedTermsPerTag)
+		}
+		if mentioned[tagKey(tag.Name)] {
+			suggestion.score += tagNameMatchBoost
+		}
+
+		if suggestion.score >= minSuggestionScore {
+			suggestion.score = math.Round(suggestion.score*1000) / 1000
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].score != suggestions[j].score {
+			return suggestions[i].score > suggestions[j].score
+		}
+		return suggestions[i].tag.Name < suggestions[j].tag.Name
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// termCounts counts the meaningful words of an article. Title words count
+// more because they describe the topic best.
+func termCounts(title, content string) map[string]int {
+	counts := make(map[string]int)
+	add := func(text string, weight int) {
+		for _, token := range suggestionToken.FindAllString(strings.ToLower(text), -1) {
+			if utf8.RuneCountInString(token) < 3 || suggestionStopwords[token] || digitsOf(token) == token {
+				continue
+			}
+			counts[token] += weight
+		}
+	}
+	add(title, suggestionTitleWeight)
+	add(utils.StripMarkup(content), 1)
+	return counts
+}
+
+// mentionedTagKeys returns the tag keys of every word and pair of adjacent
+// words in the article, so "Go", "golang" and "machine learning" in the
+// text match the tags of the same name.
+func mentionedTagKeys(title, content string) map[string]bool {
+	keys := make(map[string]bool)
+	for _, text := range []string{title, utils.StripMarkup(content)} {
+		tokens := suggestionToken.FindAllString(text, -1)
+		for i, token := range tokens {
+			keys[tagKey(token)] = true
+			if i > 0 {
+				keys[tagKey(tokens[i-1]+" "+token)] = true
+			}
+		}
+	}
+	delete(keys, "")
+	return keys
+}
+
+// topSharedTerms returns the terms that contribute most to the similarity
+// of the two vectors, to explain a suggestion.
+func topSharedTerms(a, b termVector, n int) []string {
+	type contribution struct {
+		term  string
+		value float64
+	}
+
+	var shared []contribution
+	for term, weight := range a {
+		if other, ok := b[term]; ok {
+			shared = append(shared, contribution{term, weight * other})
+		}
+	}
+	sort.Slice(shared, func(i, j int) bool {
+		if shared[i].value != shared[j].value {
+			return shared[i].value > shared[j].value
+		}
+		return shared[i].term < shared[j].term
+	})
+
+	terms := make([]string, 0, n)
+	for i := 0; i < len(shared) && i < n; i++ {
+		terms = append(terms, shared[i].term)
+	}
+	return terms
+}
+
+func (v termVector) normalized() termVector {
+	var sum float64
+	for _, weight := range v {
+		sum += weight * weight
+	}
+	if sum == 0 {
+		return v
+	}
+
+	norm := math.Sqrt(sum)
+	normalized := make(termVector, len(v))
+	for term, weight := range v {
+		normalized[term] = weight / norm
+	}
+	return normalized
+}
+
+func (v termVector) dot(other termVector) float64 {
+	if len(other) < len(v) {
+		v, other = other, v
+	}
+
+	var sum float64
+	for term, weight := range v {
+		sum += weight * other[term]
+	}
+	return sum
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+)
+
+// defaultTrafficRules ships with the binary. A file with the same layout
+// named by ANALYTICS_SOURCE_RULES replaces it, so new search engines or
+// networks can be added without a release.
+//
+//go:embed rules/traffic_sources.json
+var defaultTrafficRules []byte
+
+// trafficRules is the layout of the rules file. Domains match the host of
+// the referrer and its subdomains; "google.*" stands for google under any
+// country domain. Sources match utm_source, and mediums map utm_medium
+// values to a channel.
+type trafficRules struct {
+	Search  []trafficSourceRule `json:"search"`
+	Social  []trafficSourceRule `json:"social"`
+	Email   []trafficSourceRule `json:"email"`
+	Mediums map[string][]string `json:"mediums"`
+}
+
+type trafficSourceRule struct {
+	Name    string   `json:"name"`
+	Domains []string `json:"domains"`
+	Sources []string `json:"sources"`
+}
+
+// TrafficClassifier tells which channel a view came through, and for
+// search, social and email views which engine, network or provider.
+type TrafficClassifier interface {
+	Classify(referrerDomain, utmSource, utmMedium string) (model.TrafficSourceType, string)
+}
+
+type trafficChannel struct {
+	sourceType model.TrafficSourceType
+	rules      []trafficSourceRule
+}
+
+type trafficClassifier struct {
+	channels      []trafficChannel
+	mediums       map[string]model.TrafficSourceType
+	internalHosts map[string]bool
+}
+
+// NewTrafficClassifier loads the configured rules, or the embedded ones.
+// Referrers from the hosts of BaseURL and FrontendURL count as internal.
+func NewTrafficClassifier(cfg *config.Config) (TrafficClassifier, error) {
+	data := defaultTrafficRules
+	if cfg.AnalyticsSourceRules != "" {
+		var err error
+		if data, err = os.ReadFile(cfg.AnalyticsSourceRules); err != nil {
+			return nil, err
+		}
+	}
+
+	var rules trafficRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid traffic source rules: %w", err)
+	}
+
+	classifier := &trafficClassifier{
+		channels: []trafficChannel{
+			{model.TrafficSearch, rules.Search},
+			{model.TrafficSocial, rules.Social},
+			{model.TrafficEmail, rules.Email},
+		},
+		mediums:       make(map[string]model.TrafficSourceType),
+		internalHosts: make(map[string]bool),
+	}
+
+	for channel, mediums := range rules.Mediums {
+		sourceType := model.TrafficSourceType(channel)
+		switch sourceType {
+		case model.TrafficSearch, model.TrafficSocial, model.TrafficEmail, model.TrafficReferral:
+		default:
+			return nil, fmt.Errorf("invalid traffic source rules: unknown channel %q", channel)
+		}
+		for _, medium := range mediums {
+			classifier.mediums[strings.ToLower(medium)] = sourceType
+		}
+	}
+
+	for _, site := range []string{cfg.BaseURL, cfg.FrontendURL} {
+		if u, err := url.Parse(site); err == nil && u.Hostname() != "" {
+			classifier.internalHosts[strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")] = true
+		}
+	}
+	return classifier, nil
+}
+
+// **Classify Traffic**
+// UTM parameters win over the referrer, since campaign links are tagged on
+// purpose while many apps and mail clients send no referrer at all. A
+// tagged link from an unknown source is a referral from that source.
+func (c *trafficClassifier) Classify(referrerDomain, utmSource, utmMedium string) (model.TrafficSourceType, string) {
+	source := strings.ToLower(strings.TrimSpace(utmSource))
+	medium := strings.ToLower(strings.TrimSpace(utmMedium))
+
+	if sourceType, ok := c.mediums[medium]; ok {
+		for _, channel := range c.channels {
+			if channel.sourceType != sourceType {
+				continue
+			}
+			if rule := channel.bySource(source); rule != nil {
+				return sourceType, rule.Name
+			}
+			if rule, _ := channel.byDomain(referrerDomain); rule != nil {
+				return sourceType, rule.Name
+			}
+		}
+		if source == "" && sourceType == model.TrafficReferral {
+			return sourceType, referrerDomain
+		}
+		return sourceType, source
+	}
+
+	if source != "" {
+		for _, channel := range c.channels {
+			if rule := channel.bySource(source); rule != nil {
+				return channel.sourceType, rule.Name
+			}
+		}
+	}
+
+	switch {
+	case referrerDomain == "" && source == "":
+		return model.TrafficDirect, ""
+	case referrerDomain == "":
+		return model.TrafficReferral, source
+	case c.internalHosts[referrerDomain]:
+		return model.TrafficInternal, ""
+	}
+
+	sourceType, name, best := model.TrafficReferral, referrerDomain, 0
+	for _, channel := range c.channels {
+		if rule, score := channel.byDomain(referrerDomain); score > best {
+			sourceType, name, best = channel.sourceType, rule.Name, score
+		}
+	}
+	return sourceType, name
+}
+
+func (c trafficChannel) bySource(source string) *trafficSourceRule {
+	if source == "" {
+		return nil
+	}
+	for i, rule := range c.rules {
+		for _, candidate := range rule.Sources {
+			if strings.EqualFold(candidate, source) {
+				return &c.rules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// byDomain returns the rule whose domain matches host most specifically,
+// with a score that is higher the more specific the match, 0 for none.
+func (c trafficChannel) byDomain(host string) (*trafficSourceRule, int) {
+	if host == "" {
+		return nil, 0
+	}
+
+	var best *trafficSourceRule
+	bestScore := 0
+	for i, rule := range c.rules {
+		for _, domain := range rule.Domains {
+			if score := matchTrafficDomain(host, strings.ToLower(domain)); score > bestScore {
+				best, bestScore = &c.rules[i], score
+			}
+		}
+	}
+	return best, bestScore
+}
+
+// matchTrafficDomain matches host against a rule domain and its
+// subdomains, so mail.google.com beats google.*. A rule ending in ".*"
+// matches the name under any country or generic domain of at most two
+// short labels, such as google.co.id, and is weaker than a plain domain.
+func matchTrafficDomain(host, domain string) int {
+	name, wildcard := strings.CutSuffix(domain, ".*")
+	if !wildcard {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return 2 * len(domain)
+		}
+		return 0
+	}
+
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		if label == name && isShortDomainSuffix(labels[i+1:]) {
+			return 2*len(name) + 1
+		}
+	}
+	return 0
+}
+
+func isShortDomainSuffix(labels []string) bool {
+	if len(labels) == 0 || len(labels) > 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 3 {
+			return false
+		}
+	}
+	return true
+}
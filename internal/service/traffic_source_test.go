@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+)
+
+func TestTrafficClassifierClassify(t *testing.T) {
+	classifier, err := NewTrafficClassifier(&config.Config{
+		BaseURL:     "https://api.minddrift.id",
+		FrontendURL: "https://www.minddrift.id",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		referrer   string
+		utmSource  string
+		utmMedium  string
+		wantType   model.TrafficSourceType
+		wantSource string
+	}{
+		{"direct", "", "", "", model.TrafficDirect, ""},
+		{"internal", "minddrift.id", "", "", model.TrafficInternal, ""},
+		{"internal api host", "api.minddrift.id", "", "", model.TrafficInternal, ""},
+		{"search", "google.com", "", "", model.TrafficSearch, "google"},
+		{"search country domain", "google.co.id", "", "", model.TrafficSearch, "google"},
+		{"search subdomain", "cn.bing.com", "", "", model.TrafficSearch, "bing"},
+		{"wildcard needs a short suffix", "google.example.org", "", "", model.TrafficReferral, "google.example.org"},
+		{"social", "t.co", "", "", model.TrafficSocial, "twitter"},
+		{"social subdomain", "l.facebook.com", "", "", model.TrafficSocial, "facebook"},
+		{"email beats search", "mail.google.com", "", "", model.TrafficEmail, "gmail"},
+		{"unknown referrer", "dev.to", "", "", model.TrafficReferral, "dev.to"},
+		{"known utm source", "", "Newsletter", "", model.TrafficEmail, "newsletter"},
+		{"unknown utm source", "", "partner-blog", "", model.TrafficReferral, "partner-blog"},
+		{"utm source beats referrer", "dev.to", "linkedin", "", model.TrafficSocial, "linkedin"},
+		{"utm medium picks channel", "", "facebook", "email", model.TrafficEmail, "facebook"},
+		{"utm medium with known source", "", "gmail", "email", model.TrafficEmail, "gmail"},
+		{"utm medium with referrer", "t.co", "", "social", model.TrafficSocial, "twitter"},
+		{"referral medium without source", "dev.to", "", "affiliate", model.TrafficReferral, "dev.to"},
+		{"paid search", "", "google", "cpc", model.TrafficSearch, "google"},
+		{"unknown medium is ignored", "google.com", "", "banner", model.TrafficSearch, "google"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotSource := classifier.Classify(tt.referrer, tt.utmSource, tt.utmMedium)
+			if gotType != tt.wantType || gotSource != tt.wantSource {
+				t.Errorf("Classify(%q, %q, %q) = (%q, %q), want (%q, %q)",
+					tt.referrer, tt.utmSource, tt.utmMedium, gotType, gotSource, tt.wantType, tt.wantSource)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/dto"
 	"github.com/tsaqiffatih/minddrift-server/internal/model"
 	"github.com/tsaqiffatih/minddrift-server/internal/repository"
 	"github.com/tsaqiffatih/minddrift-server/pkg/utils"
@@ -17,28 +18,47 @@ import (
 
 type UserService interface {
 	RegisterUser(user *model.User) (*model.User, error)
-	LoginUser(email, password string) (string, error)
+	LoginUser(email, password string) (*dto.LoginResponse, error)
 	VerifyEmail(token string) error
 	ResendEmail(email string) error
-	ResetPassword(email, newPassword string) error //Change The Password
+	ResetPassword(token, newPassword string) error //Change The Password
 	ValidateTokenResetPassword(token string) error //Validate Token Reset Password
 	RequestResetPassword(email string) error       //Request Reset Password and send email
-	EnableTwoFA(userID uuid.UUID, secret string) error
-	DisableTwoFA(userID uuid.UUID) error
+	LoginTwoFA(challengeToken, code string) (*dto.LoginResponse, error)
+	SetupTwoFA(userID uuid.UUID) (*dto.TwoFASetupResponse, error)
+	EnableTwoFA(userID uuid.UUID, code string) ([]string, error)
+	DisableTwoFA(userID uuid.UUID, code string) error
 	UpdateUserProfile(user *model.User) (*model.User, error)
-	ChangeUserRole(adminID, userID uuid.UUID, newRole model.UserRole) error
+	ChangeUserRole(userID uuid.UUID, newRole model.UserRole) error
 	DeleteUser(userID uuid.UUID) error
 }
 
+const (
+	twoFAIssuer            = "MindDrift"
+	twoFAChallengeDuration = 5 * time.Minute
+	twoFAMaxAttempts       = 5
+	twoFALockDuration      = 15 * time.Minute
+	recoveryCodeCount      = 10
+
+	emailVerificationTTL = 24 * time.Hour
+	passwordResetTTL     = 30 * time.Minute
+)
+
 type userService struct {
-	repo repository.UserRepository
-	cfg  *config.Config
+	repo         repository.UserRepository
+	recoveryRepo repository.RecoveryCodeRepository
+	tokenRepo    repository.ActionTokenRepository
+	authService  AuthService
+	cfg          *config.Config
 }
 
-func NewUserService(repo repository.UserRepository, cfg *config.Config) UserService {
+func NewUserService(repo repository.UserRepository, recoveryRepo repository.RecoveryCodeRepository, tokenRepo repository.ActionTokenRepository, authService AuthService, cfg *config.Config) UserService {
 	return &userService{
-		repo: repo,
-		cfg:  cfg,
+		repo:         repo,
+		recoveryRepo: recoveryRepo,
+		tokenRepo:    tokenRepo,
+		authService:  authService,
+		cfg:          cfg,
 	}
 }
 
@@ -70,7 +90,7 @@ func (s *userService) RegisterUser(user *model.User) (*model.User, error) {
 		return nil, err
 	}
 
-	verificationToken, err := utils.GenerateTokenVerification(newUser.ID.String(), 24*time.Hour)
+	verificationToken, err := s.issueActionToken(newUser.ID, model.PurposeEmailVerification, emailVerificationTTL)
 	if err != nil {
 		return nil, err
 	}
@@ -98,56 +118,91 @@ func (s *userService) RegisterUser(user *model.User) (*model.User, error) {
 }
 
 // **Login User**
-func (s *userService) LoginUser(email, password string) (string, error) {
+func (s *userService) LoginUser(email, password string) (*dto.LoginResponse, error) {
 	user, err := s.repo.GetUserByEmail(email)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", errors.New("Invalid email or password")
-		}
 		log.Println("Error getting user by email:", err)
-		return "", errors.New("Something went wrong, please try again later")
+		return nil, errors.New("Something went wrong, please try again later")
+	}
+
+	if user == nil {
+		return nil, errors.New("Invalid email or password")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", errors.New("Invalid email or password")
+		return nil, errors.New("Invalid email or password")
 	}
 
 	if !user.EmailVerified {
-		return "", errors.New("Email has not been verified. Please check your email.")
+		return nil, errors.New("Email has not been verified. Please check your email.")
 	}
 
-	token, err := utils.GenerateJWT(s.cfg, user.ID, user.Role)
-	if err != nil {
-		log.Println("Error generating token:", err)
-		return "", errors.New("Failed to generate token")
-		// return "", errors.New("Internal server error")
+	if user.TwoFAEnabled {
+		jti, err := s.issueActionToken(user.ID, model.PurposeTwoFALogin, twoFAChallengeDuration)
+		if err != nil {
+			return nil, err
+		}
+
+		challengeToken, err := utils.GenerateChallengeToken(s.cfg, user.ID, jti, twoFAChallengeDuration)
+		if err != nil {
+			return nil, err
+		}
+
+		return &dto.LoginResponse{
+			TwoFARequired:  true,
+			ChallengeToken: challengeToken,
+		}, nil
 	}
 
-	return token, nil
+	return s.authService.IssueSession(user)
 }
 
-// **Verification Email**
-func (s *userService) VerifyEmail(token string) error {
-	emailToken, err := utils.ParseTokenVerification(token)
+// **Login Second Step (2FA)**
+// A challenge can be redeemed once; failed codes count towards the lock in
+// verifySecondFactor, so asking for new challenges does not reset it.
+func (s *userService) LoginTwoFA(challengeToken, code string) (*dto.LoginResponse, error) {
+	userID, jti, err := utils.ParseChallengeToken(s.cfg, challengeToken)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	log.Println("Email token:", emailToken)
-
-	parsedEmailToken, err := uuid.Parse(emailToken)
+	challenge, err := s.tokenRepo.GetActiveActionToken(utils.HashToken(jti), model.PurposeTwoFALogin)
 	if err != nil {
-		return errors.New("Invalid token format")
+		log.Println("Error getting challenge token:", err)
+		return nil, errors.New("Something went wrong, please try again later")
+	}
+
+	if challenge == nil || challenge.UserID != userID {
+		return nil, errors.New("Invalid or expired challenge token")
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil || user == nil || !user.TwoFAEnabled {
+		return nil, errors.New("Invalid or expired challenge token")
+	}
+
+	if err := s.verifySecondFactor(user, code); err != nil {
+		return nil, err
 	}
-	user, err := s.repo.GetUserByID(parsedEmailToken)
+
+	consumed, err := s.tokenRepo.ConsumeActionToken(challenge.ID)
 	if err != nil {
-		log.Println("Error getting user by verification token:", err)
-		return errors.New("Invalid token or token has expired")
+		log.Println("Error consuming challenge token:", err)
+		return nil, errors.New("Something went wrong, please try again later")
 	}
 
-	if user == nil {
-		log.Println("User not found by verification token")
-		return errors.New("Invalid token or token has expired")
+	if !consumed {
+		return nil, errors.New("Invalid or expired challenge token")
+	}
+
+	return s.authService.IssueSession(user)
+}
+
+// **Verification Email**
+func (s *userService) VerifyEmail(token string) error {
+	user, err := s.consumeActionToken(token, model.PurposeEmailVerification)
+	if err != nil {
+		return err
 	}
 
 	user.EmailVerified = true
@@ -161,6 +216,7 @@ func (s *userService) VerifyEmail(token string) error {
 }
 
 // **Resend Email**
+// Issuing a new verification link invalidates the previous ones.
 func (s *userService) ResendEmail(email string) error {
 	user, err := s.repo.GetUserByEmail(email)
 	if err != nil && err != gorm.ErrRecordNotFound {
@@ -175,7 +231,11 @@ func (s *userService) ResendEmail(email string) error {
 		return errors.New("Email has been verified")
 	}
 
-	verificationToken, err := utils.GenerateTokenVerification(user.ID.String(), 24*time.Hour)
+	if err := s.tokenRepo.InvalidateActionTokens(user.ID, model.PurposeEmailVerification); err != nil {
+		return err
+	}
+
+	verificationToken, err := s.issueActionToken(user.ID, model.PurposeEmailVerification, emailVerificationTTL)
 	if err != nil {
 		return err
 	}
@@ -203,6 +263,7 @@ func (s *userService) ResendEmail(email string) error {
 }
 
 // RequestResetPassword implements UserService.
+// Only the most recently requested reset link stays valid.
 func (s *userService) RequestResetPassword(email string) error {
 	user, err := s.repo.GetUserByEmail(email)
 	if err != nil && err != gorm.ErrRecordNotFound {
@@ -214,16 +275,20 @@ func (s *userService) RequestResetPassword(email string) error {
 		return nil
 	}
 
-	token, err := utils.GenerateTokenVerification(user.ID.String(), 30*time.Minute)
+	if err := s.tokenRepo.InvalidateActionTokens(user.ID, model.PurposePasswordReset); err != nil {
+		return err
+	}
+
+	token, err := s.issueActionToken(user.ID, model.PurposePasswordReset, passwordResetTTL)
 	if err != nil {
 		return err
 	}
 
 	go func() {
 		resetData := utils.EmailData{
-			Username:         user.Username,
-			VerificationLink: fmt.Sprintf("%s/verify-email?token=%s", s.cfg.FrontendURL, token),
-			MindDriftEmail:   s.cfg.MindDriftEmail,
+			Username:       user.Username,
+			ResetLink:      fmt.Sprintf("%s/reset-password?token=%s", s.cfg.FrontendURL, token),
+			MindDriftEmail: s.cfg.MindDriftEmail,
 		}
 
 		body, err := utils.GenerateEmailBody(utils.EmailResetPassword, resetData)
@@ -242,85 +307,252 @@ func (s *userService) RequestResetPassword(email string) error {
 }
 
 // ValidateTokenResetPassword implements UserService.
+// It only checks the token; the token is consumed by ResetPassword.
 func (s *userService) ValidateTokenResetPassword(token string) error {
-	userID, err := utils.ParseTokenVerification(token)
+	actionToken, err := s.tokenRepo.GetActiveActionToken(utils.HashToken(token), model.PurposePasswordReset)
 	if err != nil {
 		return err
 	}
 
-	parsedUserID, err := uuid.Parse(userID)
+	if actionToken == nil {
+		return model.ErrInvalidActionToken
+	}
+
+	return nil
+}
+
+// **Reset Password**
+// A successful reset burns every outstanding reset link and signs the user
+// out of all sessions.
+func (s *userService) ResetPassword(token, newPassword string) error {
+	err := utils.ValidatePasswordStrength(newPassword)
 	if err != nil {
-		return errors.New("Invalid Token, Your token is not registered")
+		return err
 	}
 
-	_, err = s.repo.GetUserByID(parsedUserID)
+	user, err := s.consumeActionToken(token, model.PurposePasswordReset)
 	if err != nil {
-		return errors.New("Invalid Token, Your token is not registered")
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.Password = string(hashedPassword)
+
+	if err := s.repo.UpdateUser(user); err != nil {
+		return err
+	}
+
+	if err := s.tokenRepo.InvalidateActionTokens(user.ID, model.PurposePasswordReset); err != nil {
+		log.Println("Error invalidating reset password tokens:", err)
+	}
+
+	if err := s.authService.LogoutAll(user.ID); err != nil {
+		log.Println("Error revoking sessions after password reset:", err)
 	}
 
 	return nil
 }
 
-// **Reset Password**
-func (s *userService) ResetPassword(token, newPassword string) error {
-	userID, err := utils.ParseTokenVerification(token)
+func (s *userService) issueActionToken(userID uuid.UUID, purpose model.TokenPurpose, ttl time.Duration) (string, error) {
+	token, err := utils.GenerateOpaqueToken()
 	if err != nil {
-		return err
+		log.Println("Error generating token:", err)
+		return "", errors.New("Failed to generate token")
 	}
 
-	parsedUserID, err := uuid.Parse(userID)
+	err = s.tokenRepo.CreateActionToken(&model.ActionToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
 	if err != nil {
-		return errors.New("Invalid Token, Your token is not registered")
+		log.Println("Error storing token:", err)
+		return "", errors.New("Failed to generate token")
 	}
 
-	user, err := s.repo.GetUserByID(parsedUserID)
+	return token, nil
+}
+
+// consumeActionToken validates token for purpose, marks it used and returns
+// its owner.
+func (s *userService) consumeActionToken(token string, purpose model.TokenPurpose) (*model.User, error) {
+	actionToken, err := s.tokenRepo.GetActiveActionToken(utils.HashToken(token), purpose)
 	if err != nil {
-		return errors.New("Invalid Token, Your token is not registered")
+		log.Println("Error getting token:", err)
+		return nil, errors.New("Something went wrong, please try again later")
 	}
 
-	if user == nil {
-		return errors.New("Invalid Token, Your token is not registered")
+	if actionToken == nil {
+		return nil, model.ErrInvalidActionToken
 	}
 
-	err = utils.ValidatePasswordStrength(newPassword)
+	user, err := s.repo.GetUserByID(actionToken.UserID)
+	if err != nil || user == nil {
+		return nil, model.ErrInvalidActionToken
+	}
+
+	consumed, err := s.tokenRepo.ConsumeActionToken(actionToken.ID)
 	if err != nil {
-		return err
+		log.Println("Error consuming token:", err)
+		return nil, errors.New("Something went wrong, please try again later")
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if !consumed {
+		return nil, model.ErrInvalidActionToken
+	}
+
+	return user, nil
+}
+
+// **Setup 2FA**
+// Generates a pending secret; 2FA stays disabled until EnableTwoFA confirms
+// a first code from the authenticator app.
+func (s *userService) SetupTwoFA(userID uuid.UUID) (*dto.TwoFASetupResponse, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("pengguna tidak ditemukan")
+	}
+
+	if user.TwoFAEnabled {
+		return nil, model.ErrTwoFAAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	user.Password = string(hashedPassword)
+	user.TwoFASecret = secret
+	if err := s.repo.UpdateUser(user); err != nil {
+		return nil, err
+	}
 
-	return s.repo.UpdateUser(user)
+	return &dto.TwoFASetupResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(secret, twoFAIssuer, user.Email),
+	}, nil
 }
 
 // **Activated 2FA**
-func (s *userService) EnableTwoFA(userID uuid.UUID, secret string) error {
+// Returns the plaintext recovery codes; they are only shown this once.
+func (s *userService) EnableTwoFA(userID uuid.UUID, code string) ([]string, error) {
 	user, err := s.repo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("pengguna tidak ditemukan")
+	}
+
+	if user.TwoFAEnabled {
+		return nil, model.ErrTwoFAAlreadyEnabled
+	}
+
+	if user.TwoFASecret == "" {
+		return nil, model.ErrTwoFANotSetUp
+	}
+
+	step, ok := utils.ValidateTOTP(user.TwoFASecret, code, time.Now(), user.TwoFALastStep)
+	if !ok {
+		return nil, model.ErrInvalidTwoFACode
+	}
+	user.TwoFALastStep = step
+
+	recoveryCodes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
 	if err != nil {
-		return errors.New("pengguna tidak ditemukan")
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(recoveryCodes))
+	for _, recoveryCode := range recoveryCodes {
+		hashes = append(hashes, utils.HashToken(recoveryCode))
+	}
+
+	if err := s.recoveryRepo.ReplaceRecoveryCodes(user.ID, hashes); err != nil {
+		return nil, err
 	}
 
 	user.TwoFAEnabled = true
-	user.TwoFASecret = secret
-	return s.repo.UpdateUser(user)
+	if err := s.repo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
 }
 
 // **Unactivated 2FA**
-func (s *userService) DisableTwoFA(userID uuid.UUID) error {
+func (s *userService) DisableTwoFA(userID uuid.UUID, code string) error {
 	user, err := s.repo.GetUserByID(userID)
-	if err != nil {
+	if err != nil || user == nil {
 		return errors.New("pengguna tidak ditemukan")
 	}
 
+	if !user.TwoFAEnabled {
+		return model.ErrTwoFANotSetUp
+	}
+
+	if err := s.verifySecondFactor(user, code); err != nil {
+		return err
+	}
+
+	if err := s.recoveryRepo.DeleteRecoveryCodes(user.ID); err != nil {
+		return err
+	}
+
 	user.TwoFAEnabled = false
 	user.TwoFASecret = ""
 	return s.repo.UpdateUser(user)
 }
 
+// verifySecondFactor accepts a TOTP code that was not used before or
+// consumes an unused recovery code. Every attempt is counted before the
+// code is checked and the count is cleared on success, so after
+// twoFAMaxAttempts failures the second factor is locked for
+// twoFALockDuration, however many attempts run in parallel.
+func (s *userService) verifySecondFactor(user *model.User, code string) error {
+	now := time.Now()
+	reserved, err := s.repo.ReserveTwoFAAttempt(user.ID, twoFAMaxAttempts, now, now.Add(twoFALockDuration))
+	if err != nil {
+		log.Println("Error counting second factor attempt:", err)
+		return errors.New("Something went wrong, please try again later")
+	}
+
+	if !reserved {
+		return model.ErrTwoFALocked
+	}
+
+	ok, err := s.useSecondFactor(user, code, now)
+	if err != nil {
+		log.Println("Error verifying second factor:", err)
+		return errors.New("Something went wrong, please try again later")
+	}
+
+	if !ok {
+		return model.ErrInvalidTwoFACode
+	}
+
+	if err := s.repo.ResetTwoFAFailures(user.ID); err != nil {
+		log.Println("Error resetting failed second factors:", err)
+	}
+	user.TwoFAFailedCount, user.TwoFALockedUntil = 0, nil
+
+	return nil
+}
+
+func (s *userService) useSecondFactor(user *model.User, code string, now time.Time) (bool, error) {
+	if step, ok := utils.ValidateTOTP(user.TwoFASecret, code, now, user.TwoFALastStep); ok {
+		advanced, err := s.repo.AdvanceTwoFAStep(user.ID, step)
+		if advanced {
+			user.TwoFALastStep = step
+		}
+		return advanced, err
+	}
+
+	return s.recoveryRepo.UseRecoveryCode(user.ID, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+}
+
 // **Update User Profil**
 func (s *userService) UpdateUserProfile(user *model.User) (*model.User, error) {
 	existingUser, err := s.repo.GetUserByID(user.ID)
@@ -340,18 +572,11 @@ func (s *userService) UpdateUserProfile(user *model.User) (*model.User, error) {
 	return existingUser, nil
 }
 
-// **Change Role User (Just Admin)**
-func (s *userService) ChangeUserRole(adminID, userID uuid.UUID, newRole model.UserRole) error {
-	admin, err := s.repo.GetUserByID(adminID)
-	if err != nil {
-		return errors.New("admin tidak ditemukan")
-	}
-	if admin.Role != model.Admin {
-		return errors.New("hanya admin yang dapat mengubah peran pengguna")
-	}
-
+// **Change Role User**
+// Access is enforced by the user:manage permission on the route.
+func (s *userService) ChangeUserRole(userID uuid.UUID, newRole model.UserRole) error {
 	user, err := s.repo.GetUserByID(userID)
-	if err != nil {
+	if err != nil || user == nil {
 		return errors.New("pengguna tidak ditemukan")
 	}
 
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+	"github.com/tsaqiffatih/minddrift-server/internal/repository"
+)
+
+// memoryActionTokenRepository keeps action tokens in memory with the
+// semantics of the database repository.
+type memoryActionTokenRepository struct {
+	tokens []*model.ActionToken
+
+	// beforeConsume runs just before a token is consumed, to simulate a
+	// concurrent request using it first.
+	beforeConsume func(id uuid.UUID)
+}
+
+func (r *memoryActionTokenRepository) CreateActionToken(token *model.ActionToken) error {
+	stored := *token
+	stored.ID = uuid.New()
+	r.tokens = append(r.tokens, &stored)
+	return nil
+}
+
+func (r *memoryActionTokenRepository) GetActiveActionToken(hash string, purpose model.TokenPurpose) (*model.ActionToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash && token.Purpose == purpose && token.UsedAt == nil && token.ExpiresAt.After(time.Now()) {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryActionTokenRepository) ConsumeActionToken(id uuid.UUID) (bool, error) {
+	if r.beforeConsume != nil {
+		r.beforeConsume(id)
+	}
+	for _, token := range r.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			now := time.Now()
+			token.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryActionTokenRepository) InvalidateActionTokens(userID uuid.UUID, purpose model.TokenPurpose) error {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			token.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+// expire moves the expiry of every token into the past.
+func (r *memoryActionTokenRepository) expire() {
+	for _, token := range r.tokens {
+		token.ExpiresAt = time.Now().Add(-time.Second)
+	}
+}
+
+// memoryUserRepository holds a single user.
+type memoryUserRepository struct {
+	repository.UserRepository
+	user model.User
+}
+
+func (r *memoryUserRepository) GetUserByID(id uuid.UUID) (*model.User, error) {
+	if id != r.user.ID {
+		return nil, nil
+	}
+	found := r.user
+	return &found, nil
+}
+
+func (r *memoryUserRepository) UpdateUser(user *model.User) error {
+	r.user = *user
+	return nil
+}
+
+func newTestUserService(t *testing.T) (*userService, *memoryUserRepository, *memoryActionTokenRepository) {
+	t.Helper()
+	user := model.User{ID: uuid.New(), Username: "writer", Email: "writer@example.com", Role: model.Penulis}
+	users := &memoryUserRepository{user: user}
+	tokens := &memoryActionTokenRepository{}
+	cfg := &config.Config{JWTSecret: "secret", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour}
+	auth := NewAuthService(newMemoryRefreshTokenRepository(user), cfg)
+	return NewUserService(users, nil, tokens, auth, cfg).(*userService), users, tokens
+}
+
+func issueTestToken(t *testing.T, s *userService, purpose model.TokenPurpose) string {
+	t.Helper()
+	token, err := s.issueActionToken(s.repo.(*memoryUserRepository).user.ID, purpose, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestVerifyEmailTokenIsSingleUse(t *testing.T) {
+	s, users, _ := newTestUserService(t)
+	token := issueTestToken(t, s, model.PurposeEmailVerification)
+
+	if err := s.VerifyEmail(token); err != nil {
+		t.Fatalf("first VerifyEmail() = %v", err)
+	}
+	if !users.user.EmailVerified {
+		t.Error("email not marked verified")
+	}
+	if err := s.VerifyEmail(token); !errors.Is(err, model.ErrInvalidActionToken) {
+		t.Errorf("second VerifyEmail() = %v, want %v", err, model.ErrInvalidActionToken)
+	}
+}
+
+func TestResetPasswordTokenIsSingleUse(t *testing.T) {
+	s, users, _ := newTestUserService(t)
+	token := issueTestToken(t, s, model.PurposePasswordReset)
+	other := issueTestToken(t, s, model.PurposePasswordReset)
+
+	// validating the link does not use it up
+	for i := 0; i < 2; i++ {
+		if err := s.ValidateTokenResetPassword(token); err != nil {
+			t.Fatalf("ValidateTokenResetPassword() = %v", err)
+		}
+	}
+
+	if err := s.ResetPassword(token, "NewPassw0rd"); err != nil {
+		t.Fatalf("ResetPassword() = %v", err)
+	}
+	if users.user.Password == "" || users.user.Password == "NewPassw0rd" {
+		t.Errorf("password stored as %q, want a hash", users.user.Password)
+	}
+
+	for _, reused := range []string{token, other} {
+		if err := s.ValidateTokenResetPassword(reused); !errors.Is(err, model.ErrInvalidActionToken) {
+			t.Errorf("ValidateTokenResetPassword() after reset = %v, want %v", err, model.ErrInvalidActionToken)
+		}
+		if err := s.ResetPassword(reused, "OtherPassw0rd"); !errors.Is(err, model.ErrInvalidActionToken) {
+			t.Errorf("ResetPassword() after reset = %v, want %v", err, model.ErrInvalidActionToken)
+		}
+	}
+}
+
+func TestActionTokenWrongPurpose(t *testing.T) {
+	s, users, _ := newTestUserService(t)
+	verification := issueTestToken(t, s, model.PurposeEmailVerification)
+	reset := issueTestToken(t, s, model.PurposePasswordReset)
+
+	if err := s.ResetPassword(verification, "NewPassw0rd"); !errors.Is(err, model.ErrInvalidActionToken) {
+		t.Errorf("ResetPassword(verification token) = %v, want %v", err, model.ErrInvalidActionToken)
+	}
+	if err := s.ValidateTokenResetPassword(verification); !errors.Is(err, model.ErrInvalidActionToken) {
+		t.Errorf("ValidateTokenResetPassword(verification token) = %v, want %v", err, model.ErrInvalidActionToken)
+	}
+	if err := s.VerifyEmail(reset); !errors.Is(err, model.ErrInvalidActionToken) {
+		t.Errorf("VerifyEmail(reset token) = %v, want %v", err, model.ErrInvalidActionToken)
+	}
+	if users.user.EmailVerified || users.user.Password != "" {
+		t.Fatal("a token was used for the wrong purpose")
+	}
+
+	// a rejected attempt does not burn the token for its own purpose
+	if err := s.VerifyEmail(verification); err != nil {
+		t.Errorf("VerifyEmail(verification token) = %v", err)
+	}
+}
+
+func TestActionTokenExpired(t *testing.T) {
+	s, users, tokens := newTestUserService(t)
+	verification := issueTestToken(t, s, model.PurposeEmailVerification)
+	reset := issueTestToken(t, s, model.PurposePasswordReset)
+	tokens.expire()
+
+	if err := s.VerifyEmail(verification); !errors.Is(err, model.ErrInvalidActionToken) {
+		t.Errorf("VerifyEmail() = %v, want %v", err, model.ErrInvalidActionToken)
+	}
+	if err := s.ValidateTokenResetPassword(reset); !errors.Is(err, model.ErrInvalidActionToken) {
+		t.Errorf("ValidateTokenResetPassword() = %v, want %v", err, model.ErrInvalidActionToken)
+	}
+	if err := s.ResetPassword(reset, "NewPassw0rd"); !errors.Is(err, model.ErrInvalidActionToken) {
+		t.Errorf("ResetPassword() = %v, want %v", err, model.ErrInvalidActionToken)
+	}
+	if users.user.EmailVerified || users.user.Password != "" {
+		t.Error("an expired token was accepted")
+	}
+}
+
+func TestActionTokenConsumedConcurrently(t *testing.T) {
+	s, users, tokens := newTestUserService(t)
+	token := issueTestToken(t, s, model.PurposeEmailVerification)
+
+	tokens.beforeConsume = func(id uuid.UUID) {
+		tokens.beforeConsume = nil
+		tokens.ConsumeActionToken(id)
+	}
+
+	if err := s.VerifyEmail(token); !errors.Is(err, model.ErrInvalidActionToken) {
+		t.Errorf("VerifyEmail() = %v, want %v", err, model.ErrInvalidActionToken)
+	}
+	if users.user.EmailVerified {
+		t.Error("email verified by a token another request used")
+	}
+}
+
+func TestActionTokenUnknown(t *testing.T) {
+	s, _, _ := newTestUserService(t)
+	issueTestToken(t, s, model.PurposeEmailVerification)
+
+	if err := s.VerifyEmail("not-a-token"); !errors.Is(err, model.ErrInvalidActionToken) {
+		t.Errorf("VerifyEmail() = %v, want %v", err, model.ErrInvalidActionToken)
+	}
+}
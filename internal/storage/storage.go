@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tsaqiffatih/minddrift-server/config"
+)
+
+// Storage stores uploaded files under slash-separated keys and knows the
+// public URL they are served from.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// New returns the backend selected by STORAGE_DRIVER.
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		return NewLocalStorage(cfg.UploadDir, cfg.UploadBaseURL), nil
+	case "s3":
+		return NewS3Storage(S3Options{
+			Endpoint:     cfg.S3Endpoint,
+			Region:       cfg.S3Region,
+			Bucket:       cfg.S3Bucket,
+			AccessKey:    cfg.S3AccessKey,
+			SecretKey:    cfg.S3SecretKey,
+			PublicURL:    cfg.S3PublicURL,
+			UsePathStyle: cfg.S3UsePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
@@ -0,0 +1,173 @@
+package utils
+
+import "strings"
+
+type DiffOperation string
+
+const (
+	DiffEqual  DiffOperation = "equal"
+	DiffInsert DiffOperation = "insert"
+	DiffDelete DiffOperation = "delete"
+)
+
+// diffMaxSteps bounds the search for one split point. Regions that differ
+// by more edits than that are reported as replaced wholesale, which keeps
+// the cost of diffing a rewritten article linear instead of quadratic.
+const diffMaxSteps = 1000
+
+type DiffLine struct {
+	Operation DiffOperation `json:"operation"`
+	OldLine   int           `json:"old_line,omitempty"`
+	NewLine   int           `json:"new_line,omitempty"`
+	Text      string        `json:"text"`
+}
+
+// DiffLines returns a line-level diff between oldText and newText using
+// the linear-space variant of the Myers O(ND) algorithm: instead of keeping
+// every step of the search, it finds the middle of the edit path and
+// recurses on both halves, so memory stays O(N+M). Line numbers are 1-based.
+func DiffLines(oldText, newText string) []DiffLine {
+	d := &lineDiffer{a: splitLines(oldText), b: splitLines(newText)}
+	d.diff(0, len(d.a), 0, len(d.b))
+	return d.lines
+}
+
+type lineDiffer struct {
+	a, b  []string
+	lines []DiffLine
+}
+
+func (d *lineDiffer) equal(x, y int) {
+	d.lines = append(d.lines, DiffLine{Operation: DiffEqual, OldLine: x + 1, NewLine: y + 1, Text: d.a[x]})
+}
+
+// diff appends the edits turning a[aLo:aHi] into b[bLo:bHi].
+func (d *lineDiffer) diff(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.equal(aLo, bLo)
+		aLo++
+		bLo++
+	}
+
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	if aLo < aHi && bLo < bHi {
+		if x, y, ok := d.middleSnake(aLo, aHi, bLo, bHi); ok {
+			d.diff(aLo, x, bLo, y)
+			d.diff(x, aHi, y, bHi)
+		} else {
+			d.replace(aLo, aHi, bLo, bHi)
+		}
+	} else {
+		d.replace(aLo, aHi, bLo, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.equal(aHi+i, bHi+i)
+	}
+}
+
+func (d *lineDiffer) replace(aLo, aHi, bLo, bHi int) {
+	for x := aLo; x < aHi; x++ {
+		d.lines = append(d.lines, DiffLine{Operation: DiffDelete, OldLine: x + 1, Text: d.a[x]})
+	}
+	for y := bLo; y < bHi; y++ {
+		d.lines = append(d.lines, DiffLine{Operation: DiffInsert, NewLine: y + 1, Text: d.b[y]})
+	}
+}
+
+// middleSnake runs the Myers search from both ends of the two ranges at
+// once and returns the point where the paths meet, which splits the
+// problem in two. It reports false when the ranges have nothing in common
+// or the paths do not meet within diffMaxSteps.
+func (d *lineDiffer) middleSnake(aLo, aHi, bLo, bHi int) (int, int, bool) {
+	n, m := aHi-aLo, bHi-bLo
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	size := 2*maxD + 2
+
+	forward := make([]int, size)
+	backward := make([]int, size)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	delta := n - m
+	checkForward := delta%2 != 0
+	kStart1, kEnd1, kStart2, kEnd2 := 0, 0, 0, 0
+
+	for step := 0; step < min(maxD, diffMaxSteps); step++ {
+		for k := -step + kStart1; k <= step-kEnd1; k += 2 {
+			i := offset + k
+			var x int
+			if k == -step || (k != step && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			forward[i] = x
+
+			switch {
+			case x > n:
+				kEnd1 += 2
+			case y > m:
+				kStart1 += 2
+			case checkForward:
+				j := offset + delta - k
+				if j >= 0 && j < size && backward[j] != -1 && x >= n-backward[j] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+
+		for k := -step + kStart2; k <= step-kEnd2; k += 2 {
+			i := offset + k
+			var x int
+			if k == -step || (k != step && backward[i-1] < backward[i+1]) {
+				x = backward[i+1]
+			} else {
+				x = backward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aHi-x-1] == d.b[bHi-y-1] {
+				x++
+				y++
+			}
+			backward[i] = x
+
+			switch {
+			case x > n:
+				kEnd2 += 2
+			case y > m:
+				kStart2 += 2
+			case !checkForward:
+				j := offset + delta - k
+				if j >= 0 && j < size && forward[j] != -1 {
+					fx := forward[j]
+					fy := fx - (j - offset)
+					if fx >= n-x {
+						return aLo + fx, bLo + fy, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
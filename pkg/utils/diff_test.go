@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []DiffLine
+	}{
+		{
+			name: "both empty",
+			want: nil,
+		},
+		{
+			name: "unchanged",
+			old:  "a\nb",
+			new:  "a\nb",
+			want: []DiffLine{
+				{Operation: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Operation: DiffEqual, OldLine: 2, NewLine: 2, Text: "b"},
+			},
+		},
+		{
+			name: "from empty",
+			new:  "a\nb",
+			want: []DiffLine{
+				{Operation: DiffInsert, NewLine: 1, Text: "a"},
+				{Operation: DiffInsert, NewLine: 2, Text: "b"},
+			},
+		},
+		{
+			name: "to empty",
+			old:  "a\nb",
+			want: []DiffLine{
+				{Operation: DiffDelete, OldLine: 1, Text: "a"},
+				{Operation: DiffDelete, OldLine: 2, Text: "b"},
+			},
+		},
+		{
+			name: "line replaced",
+			old:  "a\nb\nc",
+			new:  "a\nx\nc",
+			want: []DiffLine{
+				{Operation: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Operation: DiffDelete, OldLine: 2, Text: "b"},
+				{Operation: DiffInsert, NewLine: 2, Text: "x"},
+				{Operation: DiffEqual, OldLine: 3, NewLine: 3, Text: "c"},
+			},
+		},
+		{
+			name: "line inserted and deleted",
+			old:  "a\nb\nc\nd",
+			new:  "b\nc\nx\nd",
+			want: []DiffLine{
+				{Operation: DiffDelete, OldLine: 1, Text: "a"},
+				{Operation: DiffEqual, OldLine: 2, NewLine: 1, Text: "b"},
+				{Operation: DiffEqual, OldLine: 3, NewLine: 2, Text: "c"},
+				{Operation: DiffInsert, NewLine: 3, Text: "x"},
+				{Operation: DiffEqual, OldLine: 4, NewLine: 4, Text: "d"},
+			},
+		},
+		{
+			name: "windows line endings",
+			old:  "a\r\nb",
+			new:  "a\nb",
+			want: []DiffLine{
+				{Operation: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Operation: DiffEqual, OldLine: 2, NewLine: 2, Text: "b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines(%q, %q) = %+v, want %+v", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesRebuildsBothTexts(t *testing.T) {
+	var rewritten, shuffled []string
+	for i := 0; i < 3000; i++ {
+		rewritten = append(rewritten, fmt.Sprintf("new line %d", i))
+		shuffled = append(shuffled, fmt.Sprintf("line %d", (i*7)%50))
+	}
+
+	tests := []struct {
+		name string
+		old  string
+		new  string
+	}{
+		{"full rewrite", strings.Repeat("old line\n", 3000), strings.Join(rewritten, "\n")},
+		{"repeated lines", strings.Repeat("x\ny\n", 40), strings.Repeat("y\nx\n", 40)},
+		{"shuffled", strings.Join(shuffled[:500], "\n"), strings.Join(shuffled[250:], "\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var oldLines, newLines []string
+			for _, line := range DiffLines(tt.old, tt.new) {
+				if line.Operation != DiffInsert {
+					if line.OldLine != len(oldLines)+1 {
+						t.Fatalf("old line number %d, want %d", line.OldLine, len(oldLines)+1)
+					}
+					oldLines = append(oldLines, line.Text)
+				}
+				if line.Operation != DiffDelete {
+					if line.NewLine != len(newLines)+1 {
+						t.Fatalf("new line number %d, want %d", line.NewLine, len(newLines)+1)
+					}
+					newLines = append(newLines, line.Text)
+				}
+			}
+
+			if got := strings.Join(oldLines, "\n"); got != tt.old {
+				t.Errorf("old text not rebuilt")
+			}
+			if got := strings.Join(newLines, "\n"); got != tt.new {
+				t.Errorf("new text not rebuilt")
+			}
+		})
+	}
+}
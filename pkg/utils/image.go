@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"math"
+)
+
+// JPEGOrientation returns the EXIF orientation (1-8) of a JPEG, or 1 when
+// the file has none. Re-encoding drops EXIF, so the orientation has to be
+// applied to the pixels first.
+func JPEGOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // start of scan / end of image
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+
+		i += 2 + length
+	}
+
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[offset:]))
+	for n := 0; n < entries; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+// ApplyOrientation rotates and flips img so it displays upright for the
+// given EXIF orientation.
+func ApplyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	src := ToRGBA(img)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored
+				sx, sy = w-1-x, y
+			case 3: // rotated 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90° clockwise
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 90° counter-clockwise
+				sx, sy = w-1-y, x
+			}
+
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+
+	return dst
+}
+
+// ToRGBA returns img as an *image.RGBA whose bounds start at (0, 0).
+func ToRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, b.Min, draw.Src)
+	return rgba
+}
+
+// IsOpaque reports whether every pixel of img is fully opaque.
+func IsOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// ResizeToWidth scales img to the given width, keeping its aspect ratio.
+// It uses a triangle filter widened by the scale factor, so downscaling
+// averages every source pixel instead of skipping them.
+func ResizeToWidth(img image.Image, width int) *image.RGBA {
+	src := ToRGBA(img)
+	srcW, srcH := src.Rect.Dx(), src.Rect.Dy()
+
+	height := int(math.Round(float64(srcH) * float64(width) / float64(srcW)))
+	if height < 1 {
+		height = 1
+	}
+
+	// horizontal pass into a width x srcH buffer, then the vertical pass
+	tmp := image.NewRGBA(image.Rect(0, 0, width, srcH))
+	for x, w := range filterWeights(srcW, width) {
+		for y := 0; y < srcH; y++ {
+			resamplePixel(tmp.Pix[tmp.PixOffset(x, y):], src.Pix, src.PixOffset(w.start, y), 4, w.weights)
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y, w := range filterWeights(srcH, height) {
+		for x := 0; x < width; x++ {
+			resamplePixel(dst.Pix[dst.PixOffset(x, y):], tmp.Pix, tmp.PixOffset(x, w.start), tmp.Stride, w.weights)
+		}
+	}
+
+	return dst
+}
+
+type resampleWeights struct {
+	start   int
+	weights []float64
+}
+
+func filterWeights(srcSize, dstSize int) []resampleWeights {
+	scale := float64(srcSize) / float64(dstSize)
+	support := math.Max(scale, 1)
+
+	result := make([]resampleWeights, dstSize)
+	for i := range result {
+		center := (float64(i)+0.5)*scale - 0.5
+		start := int(math.Ceil(center - support))
+		end := int(math.Floor(center + support))
+		if start < 0 {
+			start = 0
+		}
+		if end > srcSize-1 {
+			end = srcSize - 1
+		}
+
+		weights := make([]float64, 0, end-start+1)
+		var sum float64
+		for j := start; j <= end; j++ {
+			weight := 1 - math.Abs(float64(j)-center)/support
+			if weight < 0 {
+				weight = 0
+			}
+			weights = append(weights, weight)
+			sum += weight
+		}
+
+		if sum == 0 {
+			// only possible at the edges, fall back to the nearest sample
+			start = min(max(int(math.Round(center)), 0), srcSize-1)
+			weights = []float64{1}
+			sum = 1
+		}
+		for k := range weights {
+			weights[k] /= sum
+		}
+
+		result[i] = resampleWeights{start: start, weights: weights}
+	}
+
+	return result
+}
+
+func resamplePixel(dst, src []uint8, offset, step int, weights []float64) {
+	var r, g, b, a float64
+	for k, weight := range weights {
+		p := src[offset+k*step:]
+		r += float64(p[0]) * weight
+		g += float64(p[1]) * weight
+		b += float64(p[2]) * weight
+		a += float64(p[3]) * weight
+	}
+
+	dst[0] = clampUint8(r)
+	dst[1] = clampUint8(g)
+	dst[2] = clampUint8(b)
+	dst[3] = clampUint8(a)
+}
+
+func clampUint8(v float64) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	default:
+		return uint8(v + 0.5)
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// exifSegment builds an APP1 segment holding a single IFD entry.
+func exifSegment(order binary.ByteOrder, tag uint16, value uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], tag)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], value)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func jpegWith(segments ...[]byte) []byte {
+	data := []byte{0xFF, 0xD8}
+	for _, segment := range segments {
+		data = append(data, segment...)
+	}
+	return append(data, 0xFF, 0xD9)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	app0 := []byte{0xFF, 0xE0, 0x00, 0x07, 'J', 'F', 'I', 'F', 0x00}
+	sos := []byte{0xFF, 0xDA, 0x00, 0x02}
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 2, 2)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"little endian", jpegWith(exifSegment(binary.LittleEndian, 0x0112, 6)), 6},
+		{"big endian", jpegWith(exifSegment(binary.BigEndian, 0x0112, 8)), 8},
+		{"after app0", jpegWith(app0, exifSegment(binary.BigEndian, 0x0112, 3)), 3},
+		{"encoded without exif", encoded.Bytes(), 1},
+		{"other tag", jpegWith(exifSegment(binary.LittleEndian, 0x0110, 6)), 1},
+		{"out of range", jpegWith(exifSegment(binary.LittleEndian, 0x0112, 9)), 1},
+		{"exif after scan", jpegWith(sos, exifSegment(binary.LittleEndian, 0x0112, 6)), 1},
+		{"truncated", jpegWith(exifSegment(binary.LittleEndian, 0x0112, 6))[:20], 1},
+		{"not a jpeg", []byte("\x89PNG\r\n\x1a\n"), 1},
+		{"empty", nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JPEGOrientation(tt.data); got != tt.want {
+				t.Errorf("JPEGOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResizeToWidth(t *testing.T) {
+	fill := color.RGBA{R: 200, G: 100, B: 50, A: 255}
+
+	tests := []struct {
+		name       string
+		srcW, srcH int
+		width      int
+		wantH      int
+	}{
+		{"downscale", 400, 300, 200, 150},
+		{"rounds height", 300, 200, 100, 67},
+		{"upscale", 10, 5, 40, 20},
+		{"minimum height", 1000, 1, 10, 1},
+		{"same size", 16, 9, 16, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := image.NewRGBA(image.Rect(0, 0, tt.srcW, tt.srcH))
+			for i := 0; i < len(src.Pix); i += 4 {
+				src.Pix[i], src.Pix[i+1], src.Pix[i+2], src.Pix[i+3] = fill.R, fill.G, fill.B, fill.A
+			}
+
+			got := ResizeToWidth(src, tt.width)
+			if got.Rect.Dx() != tt.width || got.Rect.Dy() != tt.wantH {
+				t.Fatalf("ResizeToWidth() size = %dx%d, want %dx%d", got.Rect.Dx(), got.Rect.Dy(), tt.width, tt.wantH)
+			}
+
+			// a uniform image stays uniform, so the filter weights sum to 1
+			for y := 0; y < got.Rect.Dy(); y++ {
+				for x := 0; x < got.Rect.Dx(); x++ {
+					if c := got.RGBAAt(x, y); c != fill {
+						t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, c, fill)
+					}
+				}
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package utils
 import (
 	"errors"
 	"log"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -12,19 +11,21 @@ import (
 	"github.com/tsaqiffatih/minddrift-server/internal/model"
 )
 
-var (
-	jwtSecret   = []byte(os.Getenv("JWT_SECRET"))
-	jwtAudience = os.Getenv("JWT_AUDIENCE")
-)
+// PurposeTwoFAChallenge marks the short-lived token handed out between the
+// password step and the TOTP step of a login.
+const PurposeTwoFAChallenge = "2fa_challenge"
 
 type Claims struct {
-	UserID uuid.UUID      `json:"user_id"`
-	Role   model.UserRole `json:"role"`
+	UserID  uuid.UUID      `json:"user_id"`
+	Role    model.UserRole `json:"role"`
+	Purpose string         `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(cfg *config.Config, userID uuid.UUID, role model.UserRole) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+// GenerateJWT signs an access token. The audience claim is only set when
+// JWT_AUDIENCE is configured, and VerifyJWT then requires it.
+func GenerateJWT(cfg *config.Config, userID uuid.UUID, role model.UserRole) (string, time.Time, error) {
+	expirationTime := time.Now().Add(cfg.AccessTokenTTL)
 
 	claims := &Claims{
 		UserID: userID,
@@ -32,12 +33,14 @@ func GenerateJWT(cfg *config.Config, userID uuid.UUID, role model.UserRole) (str
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "minddrift",
 			Subject:   userID.String(),
-			Audience:  []string{cfg.JWTAudience},
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
+	if cfg.JWTAudience != "" {
+		claims.Audience = jwt.ClaimStrings{cfg.JWTAudience}
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -46,10 +49,10 @@ func GenerateJWT(cfg *config.Config, userID uuid.UUID, role model.UserRole) (str
 	tokenString, err := token.SignedString(secretKey)
 	if err != nil {
 		log.Println("❌ Failed to generate JWT token:", err)
-		return "", err
+		return "", time.Time{}, err
 	}
 
-	return tokenString, nil
+	return tokenString, expirationTime, nil
 }
 
 func VerifyJWT(cfg *config.Config, tokenString string) (*Claims, error) {
@@ -70,54 +73,56 @@ func VerifyJWT(cfg *config.Config, tokenString string) (*Claims, error) {
 		return nil, jwt.ErrSignatureInvalid
 	}
 
+	if cfg.JWTAudience != "" && !claims.VerifyAudience(cfg.JWTAudience, true) {
+		return nil, errors.New("token is not intended for this audience")
+	}
+
+	// Purpose-bound tokens (e.g. 2FA challenges) are never access tokens.
+	if claims.Purpose != "" {
+		return nil, errors.New("token cannot be used for authentication")
+	}
+
 	return claims, nil
 }
 
-func GenerateTokenVerification(userID string, duration time.Duration) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(duration).Unix(),
+// GenerateChallengeToken signs a 2FA challenge. jti is stored server side
+// so the challenge can only be redeemed once.
+func GenerateChallengeToken(cfg *config.Config, userID uuid.UUID, jti string, duration time.Duration) (string, error) {
+	claims := &Claims{
+		UserID:  userID,
+		Purpose: PurposeTwoFAChallenge,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Issuer:    "minddrift",
+			Subject:   userID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	tokenString, err := token.SignedString(jwtSecret)
+	tokenString, err := token.SignedString([]byte(cfg.JWTSecret))
 	if err != nil {
-		log.Println("❌ Failed to generate token:", err)
+		log.Println("❌ Failed to generate challenge token:", err)
 		return "", errors.New("Failed to generate token")
 	}
 
 	return tokenString, nil
 }
 
-func ParseTokenVerification(tokenString string) (string, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+// ParseChallengeToken returns the user and the jti of a valid challenge.
+func ParseChallengeToken(cfg *config.Config, tokenString string) (uuid.UUID, string, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
+			return nil, jwt.ErrSignatureInvalid
 		}
-		return jwtSecret, nil
+		return []byte(cfg.JWTSecret), nil
 	})
 
-	if err != nil {
-		log.Println("❌ Failed to parse token:", err)
-		return "", errors.New("Internal server error")
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		return "", errors.New("Invalid token")
-	}
-
-	if exp, ok := claims["exp"].(float64); ok {
-		if time.Now().Unix() > int64(exp) {
-			return "", errors.New("Token has expired")
-		}
-	}
-
-	userID, ok := claims["user_id"].(string)
-	if !ok {
-		return "", errors.New("Invalid token payload")
+	if err != nil || !token.Valid || claims.Purpose != PurposeTwoFAChallenge || claims.ID == "" {
+		return uuid.Nil, "", errors.New("Invalid or expired challenge token")
 	}
 
-	return userID, nil
+	return claims.UserID, claims.ID, nil
 }
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsaqiffatih/minddrift-server/config"
+	"github.com/tsaqiffatih/minddrift-server/internal/model"
+)
+
+func TestVerifyJWTAudience(t *testing.T) {
+	tests := []struct {
+		name           string
+		issuedFor      string
+		verifiedFor    string
+		wantAuthorized bool
+	}{
+		{"no audience configured", "", "", true},
+		{"matching audience", "minddrift-web", "minddrift-web", true},
+		{"other audience", "minddrift-admin", "minddrift-web", false},
+		{"audience missing from token", "", "minddrift-web", false},
+		{"audience no longer required", "minddrift-web", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := &config.Config{JWTSecret: "secret", JWTAudience: tt.issuedFor, AccessTokenTTL: time.Minute}
+			verifier := &config.Config{JWTSecret: "secret", JWTAudience: tt.verifiedFor}
+
+			userID := uuid.New()
+			token, _, err := GenerateJWT(issuer, userID, model.Penulis)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims, err := VerifyJWT(verifier, token)
+			if (err == nil) != tt.wantAuthorized {
+				t.Fatalf("VerifyJWT() error = %v, want authorized %v", err, tt.wantAuthorized)
+			}
+			if err == nil && claims.UserID != userID {
+				t.Errorf("VerifyJWT() user = %s, want %s", claims.UserID, userID)
+			}
+		})
+	}
+}
+
+func TestVerifyJWTRejectsChallengeTokens(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "secret"}
+	token, err := GenerateChallengeToken(cfg, uuid.New(), "jti", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := VerifyJWT(cfg, token); err == nil {
+		t.Error("VerifyJWT() accepted a 2FA challenge token")
+	}
+}
@@ -14,6 +14,7 @@ import (
 type EmailData struct {
 	Username         string
 	VerificationLink string
+	ResetLink        string
 	MindDriftEmail   string
 }
 
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const maxSlugLength = 96
+
+// transliterations covers letters that do not decompose into an ASCII base
+// letter plus combining marks under NFD.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'ø': "o", 'Ø': "o", 'œ': "oe", 'Œ': "oe",
+	'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d", 'þ': "th", 'Þ': "th", 'ł': "l", 'Ł': "l",
+	'ı': "i", 'ħ': "h", 'Ħ': "h", '&': "and",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+}
+
+// Slugify turns text into a lowercase, hyphen separated ASCII slug.
+// Accented letters are reduced to their base letter and common non-Latin
+// letters are transliterated; anything else is treated as a separator.
+func Slugify(text string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	write := func(part string) {
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteString(part)
+	}
+
+	// Letters such as й decompose under NFD, so the transliterations are
+	// looked up on the composed rune first. An empty transliteration drops
+	// the letter, as with the Cyrillic hard and soft signs.
+	for _, r := range norm.NFC.String(text) {
+		if t, ok := transliterations[unicode.ToLower(r)]; ok {
+			if t != "" {
+				write(t)
+			}
+			continue
+		}
+
+		for _, d := range norm.NFD.String(string(r)) {
+			switch {
+			case unicode.Is(unicode.Mn, d):
+			case d < unicode.MaxASCII && (unicode.IsLetter(d) || unicode.IsDigit(d)):
+				write(string(unicode.ToLower(d)))
+			default:
+				if t := transliterations[unicode.ToLower(d)]; t != "" {
+					write(t)
+				} else {
+					pendingHyphen = b.Len() > 0
+				}
+			}
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+
+	return slug
+}
+
+// UniqueSlug suffixes base with -2, -3, ... until it is not among existing.
+// own is the slug the record holds now and does not count as taken.
+func UniqueSlug(base, own string, existing []string) string {
+	taken := make(map[string]bool, len(existing))
+	for _, slug := range existing {
+		taken[slug] = slug != own
+	}
+
+	candidate := base
+	for i := 2; taken[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+	return candidate
+}
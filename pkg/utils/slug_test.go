@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain", "Hello World", "hello-world"},
+		{"punctuation collapses", "  Hello,   World!  ", "hello-world"},
+		{"accents", "Crème Brûlée", "creme-brulee"},
+		{"ligatures and sharp s", "Straße & Œuvre", "strasse-and-oeuvre"},
+		{"stroked letters", "Łódź Ørsted", "lodz-orsted"},
+		{"composed cyrillic", "Ёлка и йогурт", "elka-i-yogurt"},
+		{"decomposed cyrillic", "йод", "yod"},
+		{"hard and soft signs are dropped", "объект сталь", "obekt-stal"},
+		{"greek", "Γειά σου", "geia-soy"},
+		{"digits", "Top 10 Tips 2024", "top-10-tips-2024"},
+		{"only symbols", "!!! ??? ...", ""},
+		{"unknown script", "日本語", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.text); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyTruncates(t *testing.T) {
+	got := Slugify(strings.Repeat("abcd ", 40))
+	if len(got) > maxSlugLength {
+		t.Fatalf("len(Slugify) = %d, want at most %d", len(got), maxSlugLength)
+	}
+	if strings.HasSuffix(got, "-") {
+		t.Errorf("Slugify left a trailing hyphen: %q", got)
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	tests := []struct {
+		name     string
+		own      string
+		existing []string
+		want     string
+	}{
+		{"free", "", nil, "go-tips"},
+		{"taken", "", []string{"go-tips"}, "go-tips-2"},
+		{"first free suffix", "", []string{"go-tips", "go-tips-2", "go-tips-4"}, "go-tips-3"},
+		{"own slug is not taken", "go-tips", []string{"go-tips"}, "go-tips"},
+		{"own suffix is kept", "go-tips-2", []string{"go-tips", "go-tips-2"}, "go-tips-2"},
+		{"longer slugs sharing the prefix", "", []string{"go-tips-and-tricks"}, "go-tips"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UniqueSlug("go-tips", tt.own, tt.existing); got != tt.want {
+				t.Errorf("UniqueSlug(%v) = %q, want %q", tt.existing, got, tt.want)
+			}
+		})
+	}
+}
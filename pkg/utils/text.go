@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	htmlBlockBreak = regexp.MustCompile(`(?i)</(p|div|h[1-6]|li|blockquote|pre|tr)>|<br\s*/?>`)
+	htmlTag        = regexp.MustCompile(`<[^>]*>`)
+	mdCodeFence    = regexp.MustCompile("(?s)```.*?```")
+	mdImage        = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	mdLink         = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	mdLinePrefix   = regexp.MustCompile(`(?m)^\s{0,3}(#{1,6}\s+|>\s?|[-*+]\s+|\d+\.\s+)`)
+	mdEmphasis     = regexp.MustCompile("(\\*\\*|__|\\*|`|~~)")
+	htmlHeading    = regexp.MustCompile(`(?is)<h[1-6][^>]*>.*?</h[1-6]>`)
+	mdHeading      = regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s.*$`)
+	paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+	whitespaceRun  = regexp.MustCompile(`\s+`)
+	blankLines     = regexp.MustCompile(`\n{3,}`)
+)
+
+// StripMarkup turns HTML or Markdown article content into plain text,
+// keeping paragraph breaks as blank lines.
+func StripMarkup(content string) string {
+	text := strings.ReplaceAll(content, "\r\n", "\n")
+	text = mdCodeFence.ReplaceAllString(text, "\n")
+	text = htmlBlockBreak.ReplaceAllString(text, "\n\n")
+	text = htmlTag.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = mdImage.ReplaceAllString(text, "")
+	text = mdLink.ReplaceAllString(text, "$1")
+	text = mdLinePrefix.ReplaceAllString(text, "")
+	text = mdEmphasis.ReplaceAllString(text, "")
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// FirstParagraph returns the first non-empty paragraph of the content as a
+// single line of plain text, skipping headings.
+func FirstParagraph(content string) string {
+	content = htmlHeading.ReplaceAllString(content, "")
+	content = mdHeading.ReplaceAllString(content, "")
+	for _, paragraph := range paragraphBreak.Split(StripMarkup(content), -1) {
+		if paragraph = CollapseWhitespace(paragraph); paragraph != "" {
+			return paragraph
+		}
+	}
+	return ""
+}
+
+func CollapseWhitespace(text string) string {
+	return strings.TrimSpace(whitespaceRun.ReplaceAllString(text, " "))
+}
+
+// TruncateText shortens text to at most max characters, cutting at a word
+// boundary when possible and appending "...". It reports whether text was cut.
+func TruncateText(text string, max int) (string, bool) {
+	if utf8.RuneCountInString(text) <= max {
+		return text, false
+	}
+
+	runes := []rune(text)
+	cut := string(runes[:max-3])
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:-") + "...", true
+}
+
+// Levenshtein returns the edit distance between a and b, counted in runes.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of RFC 6238 Appendix B, "12345678901234567890".
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTP(t *testing.T) {
+	// Appendix B lists 8 digit codes; the 6 digit codes are their last 6 digits.
+	tests := []struct {
+		name     string
+		unix     int64
+		code     string
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"rfc vector 59", 59, "287082", 0, 1, true},
+		{"rfc vector 1111111109", 1111111109, "081804", 0, 37037036, true},
+		{"rfc vector 1111111111", 1111111111, "050471", 0, 37037037, true},
+		{"rfc vector 1234567890", 1234567890, "005924", 0, 41152263, true},
+		{"rfc vector 2000000000", 2000000000, "279037", 0, 66666666, true},
+		{"rfc vector 20000000000", 20000000000, "353130", 0, 666666666, true},
+		{"surrounding spaces", 1234567890, " 005924 ", 0, 41152263, true},
+		{"previous step allowed", 1234567890 + 30, "005924", 0, 41152263, true},
+		{"next step allowed", 1234567890 - 30, "005924", 0, 41152263, true},
+		{"two steps late", 1234567890 + 60, "005924", 0, 0, false},
+		{"wrong code", 1234567890, "005925", 0, 0, false},
+		{"too short", 1234567890, "05924", 0, 0, false},
+		{"replayed step", 1234567890, "005924", 41152263, 0, false},
+		{"older step", 1234567890, "005924", 41152264, 0, false},
+		{"newer than last step", 1234567890, "005924", 41152262, 41152263, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(rfc6238Secret, tt.code, time.Unix(tt.unix, 0), tt.lastStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP(%q at %d) = (%d, %v), want (%d, %v)", tt.code, tt.unix, step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPInvalidSecret(t *testing.T) {
+	if _, ok := ValidateTOTP("not base32!", "123456", time.Now(), 0); ok {
+		t.Error("ValidateTOTP accepted a code for an invalid secret")
+	}
+}